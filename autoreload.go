@@ -0,0 +1,71 @@
+package cfgo
+
+import (
+	"fmt"
+	"time"
+)
+
+// StartAutoReload runs Reload every interval in the background until StopAutoReload is called. Reload errors
+// go to the error handler set with WithErrorHandler. Starting again replaces the running loop. An interval
+// that is not positive is reported to the error handler and leaves any running loop as it is.
+func (e *EnvLoader) StartAutoReload(interval time.Duration) {
+	if interval <= 0 {
+		e.handleError(fmt.Errorf("invalid auto reload interval %v, must be positive", interval))
+		return
+	}
+
+	e.autoReloadMu.Lock()
+	defer e.autoReloadMu.Unlock()
+
+	e.stopAutoReload()
+
+	stop, done := make(chan struct{}), make(chan struct{})
+	e.autoReloadStop, e.autoReloadDone = stop, done
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := e.Reload(); err != nil {
+					e.handleError(err)
+				}
+			}
+		}
+	}()
+}
+
+// StopAutoReload stops the loop started by StartAutoReload and waits for it to exit. It is safe to call
+// when no loop is running and more than once.
+func (e *EnvLoader) StopAutoReload() {
+	e.autoReloadMu.Lock()
+	defer e.autoReloadMu.Unlock()
+
+	e.stopAutoReload()
+}
+
+func (e *EnvLoader) stopAutoReload() {
+	if e.autoReloadStop == nil {
+		return
+	}
+
+	close(e.autoReloadStop)
+	<-e.autoReloadDone
+
+	e.autoReloadStop, e.autoReloadDone = nil, nil
+}
+
+func (e *EnvLoader) handleError(err error) {
+	if e.errorHandler != nil {
+		e.errorHandler(err)
+		return
+	}
+
+	e.logger.Warnf("Failed to reload config, Err: %v", err)
+}
@@ -0,0 +1,18 @@
+package cfgo
+
+import "testing"
+
+func TestStartAutoReloadRejectsNonPositiveInterval(t *testing.T) {
+	var reported []error
+
+	conf := &EnvLoader{logger: testLogger{}, envState: &envState{}}
+	conf.errorHandler = func(err error) { reported = append(reported, err) }
+
+	conf.StartAutoReload(0)
+	conf.StartAutoReload(-1)
+	conf.StopAutoReload()
+
+	if len(reported) != 2 {
+		t.Errorf("got %d reported errors, want 2", len(reported))
+	}
+}
@@ -0,0 +1,70 @@
+package cfgo
+
+import (
+	"strconv"
+	"time"
+)
+
+// Bind writes the value of key to target now and again whenever it changes. The target is written from
+// whichever goroutine made the change, such as the one running Watch or StartAutoReload, so reading it
+// concurrently needs synchronization of its own.
+func (e *EnvLoader) Bind(key string, target *string) {
+	*target = e.Get(key)
+
+	e.OnKeyChange(key, func(_, newValue string) {
+		*target = newValue
+	})
+}
+
+// BindInt is like Bind for ints. A value that does not parse, including a missing key, leaves target as it
+// was, so its initial value serves as the default.
+func (e *EnvLoader) BindInt(key string, target *int) {
+	bindValue(e, key, target, strconv.Atoi)
+}
+
+// BindFloat64 is like BindInt for float64s.
+func (e *EnvLoader) BindFloat64(key string, target *float64) {
+	bindValue(e, key, target, parseFloat64)
+}
+
+// BindBool is like BindInt for bools, accepting the same spellings as GetBool.
+func (e *EnvLoader) BindBool(key string, target *bool) {
+	bindValue(e, key, target, parseBool)
+}
+
+// BindDuration is like BindInt for durations.
+func (e *EnvLoader) BindDuration(key string, target *time.Duration) {
+	bindValue(e, key, target, time.ParseDuration)
+}
+
+func bindValue[T any](e *EnvLoader, key string, target *T, parse func(string) (T, error)) {
+	if v, err := parseValue(e, key, parse); err == nil {
+		*target = v
+	}
+
+	e.OnKeyChange(key, func(_, newValue string) {
+		if v, err := parse(newValue); err == nil {
+			*target = v
+		}
+	})
+}
+
+func Bind(key string, target *string) {
+	configInstance.Bind(key, target)
+}
+
+func BindInt(key string, target *int) {
+	configInstance.BindInt(key, target)
+}
+
+func BindFloat64(key string, target *float64) {
+	configInstance.BindFloat64(key, target)
+}
+
+func BindBool(key string, target *bool) {
+	configInstance.BindBool(key, target)
+}
+
+func BindDuration(key string, target *time.Duration) {
+	configInstance.BindDuration(key, target)
+}
@@ -0,0 +1,84 @@
+package cfgo
+
+import (
+	"slices"
+	"sync"
+)
+
+// OnChange registers a callback that receives the keys whose values changed after Reload or Set. Callbacks
+// run without the config lock held, so they may call back into the config.
+func (e *EnvLoader) OnChange(callback func(changedKeys []string)) {
+	e.Subscribe(callback)
+}
+
+// changeCallback is registered by pointer, so that Subscribe can find it again to remove it.
+type changeCallback struct {
+	fn func(names []string)
+}
+
+// Subscribe registers callback like OnChange and returns a function that removes it. Once the function
+// returns, callback is not called again, unless a notification was already running it.
+func (e *EnvLoader) Subscribe(callback func(changedKeys []string)) (unsubscribe func()) {
+	cb := &changeCallback{fn: func(names []string) {
+		if keys := e.configKeys(names); len(keys) > 0 {
+			callback(keys)
+		}
+	}}
+
+	e.mu.Lock()
+	e.callbacks = append(e.callbacks, cb)
+	e.mu.Unlock()
+
+	return func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		// Copy rather than remove in place, since notify may be iterating over the old slice
+		e.callbacks = slices.DeleteFunc(slices.Clone(e.callbacks), func(c *changeCallback) bool {
+			return c == cb
+		})
+	}
+}
+
+// OnKeyChange registers a callback that runs when the value of key changes after Reload, Set or any other
+// update that notifies OnChange callbacks. It receives the value as of the previous call, or as of
+// registration for the first one, and the new value; a missing key reads as an empty string. Any number of
+// callbacks may be registered for the same key.
+func (e *EnvLoader) OnKeyChange(key string, callback func(oldValue, newValue string)) {
+	var (
+		mu   sync.Mutex
+		last = e.Get(key)
+		want = e.canonical(key)
+	)
+
+	e.OnChange(func(changedKeys []string) {
+		if !slices.Contains(changedKeys, want) {
+			return
+		}
+
+		mu.Lock()
+		old, val := last, e.Get(key)
+		last = val
+		mu.Unlock()
+
+		if old != val {
+			callback(old, val)
+		}
+	})
+}
+
+// notify passes the names of the changed environment variables to the callbacks, each of which maps them
+// to the keys of the config it was registered on.
+func (e *EnvLoader) notify(changed []string) {
+	if len(changed) == 0 {
+		return
+	}
+
+	e.mu.Lock()
+	callbacks := e.callbacks
+	e.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback.fn(changed)
+	}
+}
@@ -1,7 +1,114 @@
 package cfgo
 
+import (
+	"context"
+	"flag"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
 type Config interface {
 	Get(string) string
+	Has(string) bool
+	HasPrefix(string) bool
 	GetOrDefault(string, string) string
 	GetArray(string) []string
+	GetArrayBy(string, string) []string
+	GetStringMapString(string) map[string]string
+	GetOrderedMapKeys(string) []string
+	GetStringMapArray(string) map[string][]string
+	GetNestedMap(string) map[string]any
+	GetObjectArray(string) []map[string]string
+	GetSub(string) Config
+	Keys() []string
+	KeysWithPrefix(string) []string
+	Range(func(key, value string) bool)
+	GetInt(string) int
+	GetInt64(string) int64
+	GetInt32(string) int32
+	GetUint(string) uint
+	GetUint32(string) uint32
+	GetUint64(string) uint64
+	GetFloat32(string) float32
+	GetFloat64(string) float64
+	GetBool(string) bool
+	GetDuration(string) time.Duration
+	GetBytes(string) int64
+	GetTime(string) (time.Time, error)
+	GetTimeLayout(string, string) (time.Time, error)
+	GetBase64(string) ([]byte, error)
+	GetHex(string) ([]byte, error)
+	GetURL(string) (*url.URL, error)
+	GetIntOrDefault(string, int) int
+	GetFloat64OrDefault(string, float64) float64
+	GetBoolOrDefault(string, bool) bool
+	GetDurationOrDefault(string, time.Duration) time.Duration
+	GetURLOrDefault(string, *url.URL) *url.URL
+	GetIntInRange(string, int, int) (int, error)
+	GetIntClamp(string, int, int) int
+	GetEnum(string, []string) (string, error)
+	GetEnumOrDefault(string, []string, string) string
+	GetIP(string) (net.IP, error)
+	GetCIDR(string) (*net.IPNet, error)
+	GetCIDRArray(string) ([]*net.IPNet, error)
+	GetIntArray(string) []int
+	GetFloat64Array(string) []float64
+	GetDurationArray(string) []time.Duration
+	GetDurationArrayE(string) ([]time.Duration, error)
+	GetIntE(string) (int, error)
+	GetInt64E(string) (int64, error)
+	GetInt32E(string) (int32, error)
+	GetUintE(string) (uint, error)
+	GetUint32E(string) (uint32, error)
+	GetUint64E(string) (uint64, error)
+	GetFloat32E(string) (float32, error)
+	GetFloat64E(string) (float64, error)
+	GetBoolE(string) (bool, error)
+	GetDurationE(string) (time.Duration, error)
+	GetBytesE(string) (int64, error)
+	GetParsed(string, string) (any, error)
+	GetJSON(string, any) error
+	Unmarshal(any) error
+	WatchUnmarshal(any, sync.Locker, func(err error)) (func(), error)
+	Validate(map[string]func(any) error) error
+	Require(...string) error
+	Set(string, string) error
+	SetMany(map[string]string) error
+	SetDefault(string, string)
+	Alias(string, string)
+	DeprecateKey(string, string)
+	GetOrSet(string, func() string) string
+	Unset(string) error
+	Reload() error
+	ReloadContext(context.Context) error
+	ReloadIfChanged() (bool, error)
+	LoadEnvFile(string) error
+	LoadFlags(*flag.FlagSet) error
+	FromReader(io.Reader, string) error
+	LoadJSON(...string) error
+	LoadYAML(...string) error
+	LoadTOML(...string) error
+	LoadINI(...string) error
+	LoadProperties(...string) error
+	Snapshot() map[string]string
+	MarkSecret(...string)
+	Dump() map[string]string
+	WriteEnvFile(string) error
+	WriteJSON(io.Writer, ...JSONOption) error
+	Restore(map[string]string) error
+	Diff(Config) map[string][2]any
+	OnChange(func(changedKeys []string))
+	Subscribe(func(changedKeys []string)) func()
+	OnKeyChange(string, func(oldValue, newValue string))
+	Bind(string, *string)
+	BindInt(string, *int)
+	BindFloat64(string, *float64)
+	BindBool(string, *bool)
+	BindDuration(string, *time.Duration)
+	Watch(time.Duration, func()) (io.Closer, error)
+	StartAutoReload(time.Duration)
+	StopAutoReload()
 }
@@ -0,0 +1,15 @@
+// Package cfgo reads configuration from the process environment, which it populates from env files.
+//
+// Values are layered in this order, later layers overriding earlier ones:
+//
+//  0. Defaults registered with SetDefault, which are never exported to the environment.
+//  1. The first env file ('.env' by default), for variables that are not already set.
+//  2. Variables already present in the environment when the config is created.
+//  3. The remaining env files in order ('.local.env' or '.{APP_ENV}.env' by default).
+//  4. Values applied at runtime with Set, SetMany, GetOrSet, LoadEnvFile, LoadFlags, FromReader, LoadJSON,
+//     LoadYAML, LoadTOML, LoadINI, LoadProperties or an HTTPLoader, which Reload keeps until Unset.
+//
+// WithSystemEnvPriority moves layer 2 above or below all the env files, and WithoutSystemEnv drops it.
+// Reload re-applies layers 1 and 3. With WithProfile, a key under the profile, such as prod.db.host, takes
+// precedence over its base key whichever layer either came from.
+package cfgo
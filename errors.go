@@ -0,0 +1,36 @@
+package cfgo
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrKeyNotFound is returned, wrapped in a ConfigError, when a key is missing or empty.
+	ErrKeyNotFound = errors.New("config key not found")
+	// ErrInvalidValue is matched by the ConfigError returned when a value cannot be converted.
+	ErrInvalidValue = errors.New("invalid config value")
+)
+
+// ConfigError is the error returned by the getters that report errors. Err is ErrKeyNotFound for a missing
+// key and the conversion error otherwise, in which case errors.Is also matches ErrInvalidValue.
+type ConfigError struct {
+	Key string
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	if errors.Is(e.Err, ErrKeyNotFound) {
+		return fmt.Sprintf("config key %q is not set", e.Key)
+	}
+
+	return fmt.Sprintf("invalid value for config key %q: %v", e.Key, e.Err)
+}
+
+func (e *ConfigError) Unwrap() []error {
+	if errors.Is(e.Err, ErrKeyNotFound) {
+		return []error{e.Err}
+	}
+
+	return []error{ErrInvalidValue, e.Err}
+}
@@ -0,0 +1,22 @@
+package cfgo
+
+import "flag"
+
+// LoadFlags sets the flags that were set on the parsed fs in the config, keyed by flag name, so --db.host=x
+// becomes db.host. Flags left at their default are skipped so they do not hide lower layers. Flags override
+// variables that are already set and are remembered, so Reload, Watch and StartAutoReload never replace
+// them with values from the env files, which makes them the highest precedence layer. The OnChange
+// callbacks receive every key that changed.
+func (e *EnvLoader) LoadFlags(fs *flag.FlagSet) error {
+	values := make(map[string]string)
+
+	fs.Visit(func(f *flag.Flag) {
+		values[e.envName(f.Name)] = f.Value.String()
+	})
+
+	return e.merge(values)
+}
+
+func LoadFlags(fs *flag.FlagSet) error {
+	return configInstance.LoadFlags(fs)
+}
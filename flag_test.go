@@ -0,0 +1,44 @@
+package cfgo
+
+import (
+	"flag"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFlagsSurvivesReload(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("APP_ENV", "")
+	unsetEnv(t, "db.host")
+
+	writeFile(t, filepath.Join(dir, ".env"), "db.host=file\n")
+	writeFile(t, filepath.Join(dir, ".local.env"), "db.host=local\n")
+
+	conf := NewEnvFile(dir, testLogger{})
+
+	var changed []string
+	conf.OnChange(func(keys []string) { changed = append(changed, keys...) })
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("db.host", "", "")
+
+	if err := fs.Parse([]string{"--db.host=flag"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conf.LoadFlags(fs); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changed) != 1 || changed[0] != "db.host" {
+		t.Errorf("OnChange got %v, want [db.host]", changed)
+	}
+
+	if err := conf.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := conf.Get("db.host"); got != "flag" {
+		t.Errorf("after reload Get() = %q, want %q", got, "flag")
+	}
+}
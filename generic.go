@@ -0,0 +1,88 @@
+package cfgo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// GetAs reads key from cfg and converts it to T, which must be one of string, int, int64, float64, bool,
+// time.Duration or []string. A missing or empty key is an error, as is a value that does not convert or
+// an unsupported T.
+func GetAs[T any](cfg Config, key string) (T, error) {
+	parse, ok := parserFor[T]()
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("unsupported type %v for config key %q", reflect.TypeOf((*T)(nil)).Elem(), key)
+	}
+
+	return parseValue(cfg, key, parse)
+}
+
+// GetMapAs returns the keys under "<key>." with the prefix stripped, each value converted to V as GetAs
+// would. Conversion does not stop at the first bad value: every entry that converts is returned, and the
+// error lists each key that did not.
+func GetMapAs[V any](cfg Config, key string) (map[string]V, error) {
+	parse, ok := parserFor[V]()
+	if !ok {
+		return nil, fmt.Errorf("unsupported type %v for config key %q", reflect.TypeOf((*V)(nil)).Elem(), key)
+	}
+
+	raw := cfg.GetStringMapString(key)
+
+	keys := make([]string, 0, len(raw))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	var (
+		result = make(map[string]V, len(raw))
+		errs   []error
+	)
+
+	for _, k := range keys {
+		v, err := parse(raw[k])
+		if err != nil {
+			errs = append(errs, &ConfigError{Key: key + "." + k, Err: err})
+			continue
+		}
+
+		result[k] = v
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// parserFor returns the function GetAs uses to convert a raw value to T, and false if T is unsupported.
+func parserFor[T any]() (func(string) (T, error), bool) {
+	var (
+		zero T
+		fn   any
+	)
+
+	switch any(zero).(type) {
+	case string:
+		fn = func(s string) (string, error) { return s, nil }
+	case int:
+		fn = strconv.Atoi
+	case int64:
+		fn = parseInt64
+	case float64:
+		fn = parseFloat64
+	case bool:
+		fn = parseBool
+	case time.Duration:
+		fn = time.ParseDuration
+	case []string:
+		fn = func(s string) ([]string, error) { return splitArray(s, defaultSeparator), nil }
+	}
+
+	parse, ok := fn.(func(string) (T, error))
+
+	return parse, ok
+}
@@ -0,0 +1,64 @@
+package cfgo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetAs(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	t.Setenv("CFGO_TEST_STRING", "hello")
+	t.Setenv("CFGO_TEST_INT", "42")
+	t.Setenv("CFGO_TEST_INT64", "9000000000")
+	t.Setenv("CFGO_TEST_FLOAT", "2.5")
+	t.Setenv("CFGO_TEST_BOOL", "yes")
+	t.Setenv("CFGO_TEST_DURATION", "1m30s")
+	t.Setenv("CFGO_TEST_LIST", "a, b")
+	t.Setenv("CFGO_TEST_BAD", "x")
+	unsetEnv(t, "CFGO_TEST_MISSING")
+
+	conf := NewEnvFile(t.TempDir(), testLogger{})
+
+	check := func(name string, got, want any, err error) {
+		t.Helper()
+
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("GetAs[%s]() = %v, %v, want %v, nil", name, got, err, want)
+		}
+	}
+
+	s, err := GetAs[string](conf, "CFGO_TEST_STRING")
+	check("string", s, "hello", err)
+
+	i, err := GetAs[int](conf, "CFGO_TEST_INT")
+	check("int", i, 42, err)
+
+	i64, err := GetAs[int64](conf, "CFGO_TEST_INT64")
+	check("int64", i64, int64(9000000000), err)
+
+	f, err := GetAs[float64](conf, "CFGO_TEST_FLOAT")
+	check("float64", f, 2.5, err)
+
+	b, err := GetAs[bool](conf, "CFGO_TEST_BOOL")
+	check("bool", b, true, err)
+
+	d, err := GetAs[time.Duration](conf, "CFGO_TEST_DURATION")
+	check("time.Duration", d, 90*time.Second, err)
+
+	list, err := GetAs[[]string](conf, "CFGO_TEST_LIST")
+	check("[]string", list, []string{"a", "b"}, err)
+
+	if _, err := GetAs[int](conf, "CFGO_TEST_BAD"); err == nil {
+		t.Error("GetAs[int]() of an invalid value returned no error")
+	}
+
+	if _, err := GetAs[int](conf, "CFGO_TEST_MISSING"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("GetAs[int]() of a missing key error = %v, want ErrKeyNotFound", err)
+	}
+
+	if _, err := GetAs[uint8](conf, "CFGO_TEST_INT"); err == nil {
+		t.Error("GetAs[uint8]() returned no error for an unsupported type")
+	}
+}
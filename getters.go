@@ -0,0 +1,545 @@
+package cfgo
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (e *EnvLoader) GetInt(key string) int {
+	v, _ := e.GetIntE(key)
+	return v
+}
+
+func (e *EnvLoader) GetInt64(key string) int64 {
+	v, _ := e.GetInt64E(key)
+	return v
+}
+
+func (e *EnvLoader) GetInt32(key string) int32 {
+	v, _ := e.GetInt32E(key)
+	return v
+}
+
+func (e *EnvLoader) GetUint(key string) uint {
+	v, _ := e.GetUintE(key)
+	return v
+}
+
+func (e *EnvLoader) GetUint32(key string) uint32 {
+	v, _ := e.GetUint32E(key)
+	return v
+}
+
+func (e *EnvLoader) GetUint64(key string) uint64 {
+	v, _ := e.GetUint64E(key)
+	return v
+}
+
+func (e *EnvLoader) GetFloat32(key string) float32 {
+	v, _ := e.GetFloat32E(key)
+	return v
+}
+
+func (e *EnvLoader) GetFloat64(key string) float64 {
+	v, _ := e.GetFloat64E(key)
+	return v
+}
+
+func (e *EnvLoader) GetBool(key string) bool {
+	v, _ := e.GetBoolE(key)
+	return v
+}
+
+func (e *EnvLoader) GetDuration(key string) time.Duration {
+	v, _ := e.GetDurationE(key)
+	return v
+}
+
+func (e *EnvLoader) GetBytes(key string) int64 {
+	v, _ := e.GetBytesE(key)
+	return v
+}
+
+func (e *EnvLoader) GetIntE(key string) (int, error) {
+	return parseValue(e, key, strconv.Atoi)
+}
+
+func (e *EnvLoader) GetInt64E(key string) (int64, error) {
+	return parseValue(e, key, parseInt64)
+}
+
+func (e *EnvLoader) GetInt32E(key string) (int32, error) {
+	return parseValue(e, key, func(s string) (int32, error) {
+		v, err := strconv.ParseInt(s, 10, 32)
+		return int32(v), err
+	})
+}
+
+func (e *EnvLoader) GetUintE(key string) (uint, error) {
+	return parseValue(e, key, func(s string) (uint, error) {
+		v, err := strconv.ParseUint(s, 10, strconv.IntSize)
+		return uint(v), err
+	})
+}
+
+func (e *EnvLoader) GetUint32E(key string) (uint32, error) {
+	return parseValue(e, key, func(s string) (uint32, error) {
+		v, err := strconv.ParseUint(s, 10, 32)
+		return uint32(v), err
+	})
+}
+
+func (e *EnvLoader) GetUint64E(key string) (uint64, error) {
+	return parseValue(e, key, func(s string) (uint64, error) {
+		return strconv.ParseUint(s, 10, 64)
+	})
+}
+
+func (e *EnvLoader) GetFloat32E(key string) (float32, error) {
+	return parseValue(e, key, func(s string) (float32, error) {
+		v, err := strconv.ParseFloat(s, 32)
+		return float32(v), err
+	})
+}
+
+func (e *EnvLoader) GetFloat64E(key string) (float64, error) {
+	return parseValue(e, key, parseFloat64)
+}
+
+func (e *EnvLoader) GetBoolE(key string) (bool, error) {
+	return parseValue(e, key, parseBool)
+}
+
+func (e *EnvLoader) GetDurationE(key string) (time.Duration, error) {
+	return parseValue(e, key, time.ParseDuration)
+}
+
+// GetBytesE parses a byte size such as 512, 25MB or 1.5GiB. The KB, MB, GB and TB suffixes are 1000-based,
+// KiB, MiB, GiB and TiB are 1024-based, and suffixes are case-insensitive.
+func (e *EnvLoader) GetBytesE(key string) (int64, error) {
+	return parseValue(e, key, parseByteSize)
+}
+
+func (e *EnvLoader) GetTime(key string) (time.Time, error) {
+	return e.GetTimeLayout(key, time.RFC3339)
+}
+
+func (e *EnvLoader) GetTimeLayout(key, layout string) (time.Time, error) {
+	return parseValue(e, key, func(s string) (time.Time, error) {
+		return time.Parse(layout, s)
+	})
+}
+
+// GetBase64 decodes a base64 value, accepting the standard and URL-safe alphabets with or without padding.
+func (e *EnvLoader) GetBase64(key string) ([]byte, error) {
+	return parseValue(e, key, parseBase64)
+}
+
+func (e *EnvLoader) GetHex(key string) ([]byte, error) {
+	return parseValue(e, key, hex.DecodeString)
+}
+
+func (e *EnvLoader) GetURL(key string) (*url.URL, error) {
+	return parseValue(e, key, url.Parse)
+}
+
+func (e *EnvLoader) GetIntOrDefault(key string, defaultValue int) int {
+	if v, err := e.GetIntE(key); err == nil {
+		return v
+	}
+
+	return defaultValue
+}
+
+func (e *EnvLoader) GetFloat64OrDefault(key string, defaultValue float64) float64 {
+	if v, err := e.GetFloat64E(key); err == nil {
+		return v
+	}
+
+	return defaultValue
+}
+
+func (e *EnvLoader) GetBoolOrDefault(key string, defaultValue bool) bool {
+	if v, err := e.GetBoolE(key); err == nil {
+		return v
+	}
+
+	return defaultValue
+}
+
+func (e *EnvLoader) GetDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	if v, err := e.GetDurationE(key); err == nil {
+		return v
+	}
+
+	return defaultValue
+}
+
+// GetURLOrDefault returns defaultValue when the key is absent, empty or not a valid URL.
+func (e *EnvLoader) GetURLOrDefault(key string, defaultValue *url.URL) *url.URL {
+	if v, err := e.GetURL(key); err == nil {
+		return v
+	}
+
+	return defaultValue
+}
+
+// GetIntInRange is like GetIntE, but also reports a value outside [min, max] as an error.
+func (e *EnvLoader) GetIntInRange(key string, min, max int) (int, error) {
+	return parseValue(e, key, func(s string) (int, error) {
+		v, err := strconv.Atoi(s)
+		if err == nil && (v < min || v > max) {
+			err = fmt.Errorf("%d is outside the range [%d, %d]", v, min, max)
+		}
+
+		return v, err
+	})
+}
+
+// GetIntClamp is like GetInt, but limits the value to [min, max]. A missing or invalid value reads as 0
+// before clamping.
+func (e *EnvLoader) GetIntClamp(key string, min, max int) int {
+	return clamp(e.GetInt(key), min, max)
+}
+
+// GetEnum returns the value of key if it is one of allowed, and an error listing the allowed values
+// otherwise. Values are matched case-insensitively and returned as spelled in allowed, so with allowed
+// values debug and info, LOG_LEVEL=INFO reads as info.
+func (e *EnvLoader) GetEnum(key string, allowed []string) (string, error) {
+	return parseValue(e, key, func(s string) (string, error) {
+		if slices.Contains(allowed, s) {
+			return s, nil
+		}
+
+		for _, a := range allowed {
+			if strings.EqualFold(a, s) {
+				return a, nil
+			}
+		}
+
+		return "", fmt.Errorf("%q is not one of %s", s, strings.Join(allowed, ", "))
+	})
+}
+
+// GetEnumOrDefault returns defaultValue when the key is absent, empty or not one of allowed.
+func (e *EnvLoader) GetEnumOrDefault(key string, allowed []string, defaultValue string) string {
+	if v, err := e.GetEnum(key, allowed); err == nil {
+		return v
+	}
+
+	return defaultValue
+}
+
+// GetIntArray splits the value like GetArray and parses each element, skipping elements that are not
+// valid ints. A missing or empty key returns an empty slice.
+func (e *EnvLoader) GetIntArray(key string) []int {
+	return parseArray(e.GetArray(key), strconv.Atoi)
+}
+
+// GetFloat64Array splits the value like GetArray and parses each element, skipping elements that are not
+// valid floats. A missing or empty key returns an empty slice.
+func (e *EnvLoader) GetFloat64Array(key string) []float64 {
+	return parseArray(e.GetArray(key), parseFloat64)
+}
+
+// GetDurationArray splits the value like GetArray and parses each element, skipping elements that are not
+// valid durations. A missing or empty key returns an empty slice, as GetDurationArrayE does.
+func (e *EnvLoader) GetDurationArray(key string) []time.Duration {
+	return parseArray(e.GetArray(key), time.ParseDuration)
+}
+
+// GetDurationArrayE is like GetDurationArray, but reports the first element that is not a valid duration
+// instead of skipping it. A missing or empty key returns an empty slice.
+func (e *EnvLoader) GetDurationArrayE(key string) ([]time.Duration, error) {
+	items := e.GetArray(key)
+
+	result := make([]time.Duration, 0, len(items))
+	for i, item := range items {
+		d, err := time.ParseDuration(item)
+		if err != nil {
+			return nil, &ConfigError{Key: key, Err: fmt.Errorf("element %d: %w", i, err)}
+		}
+
+		result = append(result, d)
+	}
+
+	return result, nil
+}
+
+// parseValue looks up key and converts it with parse. A missing or empty key is reported as a ConfigError
+// wrapping ErrKeyNotFound, and a value parse rejects as one wrapping the parse error.
+func parseValue[T any](cfg Config, key string, parse func(string) (T, error)) (T, error) {
+	var zero T
+
+	raw := cfg.Get(key)
+	if raw == "" {
+		return zero, &ConfigError{Key: key, Err: ErrKeyNotFound}
+	}
+
+	v, err := parse(raw)
+	if err != nil {
+		return zero, &ConfigError{Key: key, Err: err}
+	}
+
+	return v, nil
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+
+	if v > max {
+		return max
+	}
+
+	return v
+}
+
+// parseArray parses each item, skipping those that fail. It returns an empty, non-nil slice for no items.
+func parseArray[T any](items []string, parse func(string) (T, error)) []T {
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		if v, err := parse(item); err == nil {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+func parseByteSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+
+	n, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+
+	unit, ok := byteUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size unit in %q", s)
+	}
+
+	if size := n * unit; size < math.MaxInt64 {
+		return int64(size), nil
+	}
+
+	return 0, fmt.Errorf("byte size %q overflows int64", s)
+}
+
+func parseBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	if strings.ContainsAny(s, "-_") {
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+
+	return base64.RawStdEncoding.DecodeString(s)
+}
+
+// parseBool accepts true, t, 1, yes and on as true and false, f, 0, no and off as false, in any case.
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "t", "1", "yes", "on":
+		return true, nil
+	case "false", "f", "0", "no", "off":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
+
+func parseInt64(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
+
+func parseFloat64(s string) (float64, error) {
+	return strconv.ParseFloat(s, 64)
+}
+
+func GetInt(key string) int {
+	return configInstance.GetInt(key)
+}
+
+func GetInt64(key string) int64 {
+	return configInstance.GetInt64(key)
+}
+
+func GetInt32(key string) int32 {
+	return configInstance.GetInt32(key)
+}
+
+func GetUint(key string) uint {
+	return configInstance.GetUint(key)
+}
+
+func GetUint32(key string) uint32 {
+	return configInstance.GetUint32(key)
+}
+
+func GetUint64(key string) uint64 {
+	return configInstance.GetUint64(key)
+}
+
+func GetFloat32(key string) float32 {
+	return configInstance.GetFloat32(key)
+}
+
+func GetFloat64(key string) float64 {
+	return configInstance.GetFloat64(key)
+}
+
+func GetBool(key string) bool {
+	return configInstance.GetBool(key)
+}
+
+func GetDuration(key string) time.Duration {
+	return configInstance.GetDuration(key)
+}
+
+func GetTime(key string) (time.Time, error) {
+	return configInstance.GetTime(key)
+}
+
+func GetTimeLayout(key, layout string) (time.Time, error) {
+	return configInstance.GetTimeLayout(key, layout)
+}
+
+func GetBase64(key string) ([]byte, error) {
+	return configInstance.GetBase64(key)
+}
+
+func GetHex(key string) ([]byte, error) {
+	return configInstance.GetHex(key)
+}
+
+func GetURL(key string) (*url.URL, error) {
+	return configInstance.GetURL(key)
+}
+
+func GetURLOrDefault(key string, defaultValue *url.URL) *url.URL {
+	return configInstance.GetURLOrDefault(key, defaultValue)
+}
+
+func GetIntInRange(key string, min, max int) (int, error) {
+	return configInstance.GetIntInRange(key, min, max)
+}
+
+func GetIntClamp(key string, min, max int) int {
+	return configInstance.GetIntClamp(key, min, max)
+}
+
+func GetEnum(key string, allowed []string) (string, error) {
+	return configInstance.GetEnum(key, allowed)
+}
+
+func GetEnumOrDefault(key string, allowed []string, defaultValue string) string {
+	return configInstance.GetEnumOrDefault(key, allowed, defaultValue)
+}
+
+func GetIntOrDefault(key string, defaultValue int) int {
+	return configInstance.GetIntOrDefault(key, defaultValue)
+}
+
+func GetFloat64OrDefault(key string, defaultValue float64) float64 {
+	return configInstance.GetFloat64OrDefault(key, defaultValue)
+}
+
+func GetBoolOrDefault(key string, defaultValue bool) bool {
+	return configInstance.GetBoolOrDefault(key, defaultValue)
+}
+
+func GetDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	return configInstance.GetDurationOrDefault(key, defaultValue)
+}
+
+func GetIntArray(key string) []int {
+	return configInstance.GetIntArray(key)
+}
+
+func GetFloat64Array(key string) []float64 {
+	return configInstance.GetFloat64Array(key)
+}
+
+func GetDurationArray(key string) []time.Duration {
+	return configInstance.GetDurationArray(key)
+}
+
+func GetDurationArrayE(key string) ([]time.Duration, error) {
+	return configInstance.GetDurationArrayE(key)
+}
+
+func GetBytes(key string) int64 {
+	return configInstance.GetBytes(key)
+}
+
+func GetBytesE(key string) (int64, error) {
+	return configInstance.GetBytesE(key)
+}
+
+func GetIntE(key string) (int, error) {
+	return configInstance.GetIntE(key)
+}
+
+func GetInt64E(key string) (int64, error) {
+	return configInstance.GetInt64E(key)
+}
+
+func GetInt32E(key string) (int32, error) {
+	return configInstance.GetInt32E(key)
+}
+
+func GetUintE(key string) (uint, error) {
+	return configInstance.GetUintE(key)
+}
+
+func GetUint32E(key string) (uint32, error) {
+	return configInstance.GetUint32E(key)
+}
+
+func GetUint64E(key string) (uint64, error) {
+	return configInstance.GetUint64E(key)
+}
+
+func GetFloat32E(key string) (float32, error) {
+	return configInstance.GetFloat32E(key)
+}
+
+func GetFloat64E(key string) (float64, error) {
+	return configInstance.GetFloat64E(key)
+}
+
+func GetBoolE(key string) (bool, error) {
+	return configInstance.GetBoolE(key)
+}
+
+func GetDurationE(key string) (time.Duration, error) {
+	return configInstance.GetDurationE(key)
+}
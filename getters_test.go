@@ -0,0 +1,65 @@
+package cfgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNumericArraysOfMissingKeyAreEmpty(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	unsetEnv(t, "CFGO_TEST_MISSING")
+
+	conf := NewEnvFile(t.TempDir(), testLogger{})
+
+	if got := conf.GetIntArray("CFGO_TEST_MISSING"); got == nil || len(got) != 0 {
+		t.Errorf("GetIntArray() = %#v, want empty non-nil slice", got)
+	}
+
+	if got := conf.GetFloat64Array("CFGO_TEST_MISSING"); got == nil || len(got) != 0 {
+		t.Errorf("GetFloat64Array() = %#v, want empty non-nil slice", got)
+	}
+
+	if got := conf.GetDurationArray("CFGO_TEST_MISSING"); got == nil || len(got) != 0 {
+		t.Errorf("GetDurationArray() = %#v, want empty non-nil slice", got)
+	}
+
+	if got, err := conf.GetDurationArrayE("CFGO_TEST_MISSING"); err != nil || got == nil || len(got) != 0 {
+		t.Errorf("GetDurationArrayE() = %#v, %v, want empty non-nil slice", got, err)
+	}
+}
+
+func TestGetIntArraySkipsInvalidElements(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	t.Setenv("CFGO_TEST_INTS", "100, x, 400")
+
+	conf := NewEnvFile(t.TempDir(), testLogger{})
+
+	if got, want := conf.GetIntArray("CFGO_TEST_INTS"), []int{100, 400}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetIntArray() = %v, want %v", got, want)
+	}
+}
+
+func TestParseBool(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"true", true}, {"TRUE", true}, {"True", true}, {"t", true}, {"T", true}, {"1", true},
+		{"yes", true}, {"YES", true}, {"on", true}, {"On", true}, {" true ", true},
+		{"false", false}, {"FALSE", false}, {"False", false}, {"f", false}, {"F", false}, {"0", false},
+		{"no", false}, {"NO", false}, {"off", false}, {"Off", false}, {" off ", false},
+	}
+
+	for _, tt := range tests {
+		got, err := parseBool(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("parseBool(%q) = %v, %v, want %v, nil", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "maybe", "2", "y", "n", "enabled"} {
+		if _, err := parseBool(in); err == nil {
+			t.Errorf("parseBool(%q) returned no error", in)
+		}
+	}
+}
@@ -2,4 +2,11 @@ module github.com/anxuanzi/cfgo
 
 go 1.22.3
 
-require github.com/joho/godotenv v1.5.1 // indirect
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/fsnotify/fsnotify v1.7.0
+	github.com/joho/godotenv v1.5.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/sys v0.4.0 // indirect
@@ -1,20 +1,99 @@
 package cfgo
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/joho/godotenv"
 )
 
 const (
-	defaultFileName         = "/.env"
-	defaultOverrideFileName = "/.local.env"
+	defaultFileName         = ".env"
+	defaultOverrideFileName = ".local.env"
+	defaultSeparator        = ","
+	defaultEnvVar           = "APP_ENV"
 )
 
 type EnvLoader struct {
 	logger logger
+	folder string
+	prefix string
+	// envFiles replaces the default env file names when set.
+	envFiles []string
+	// envVar names the variable that selects the environment, APP_ENV unless set.
+	envVar     string
+	defaultEnv string
+	// foldCase makes key lookups case-insensitive, with upper case as the canonical form.
+	foldCase         bool
+	noDefaultSecrets bool
+	errorHandler     func(error)
+	// profile keys, such as prod.db.host for the profile prod, override the base keys when set.
+	profile    string
+	envProfile bool
+	// profilePrefix is to profile keys what prefix is to base keys.
+	profilePrefix string
+	// keySep stands for the dots of keys in variable names, such as "__" for DB__HOST; keyLower maps those
+	// names to lower-case keys.
+	keySep            string
+	keyLower          bool
+	systemEnvPriority SystemEnvPriority
+	dotenvNaming      bool
+	observer          Observer
+	templates         bool
+	fsys              fs.FS
+	// deprecationHandler receives the messages of DeprecateKey instead of the logger when set.
+	deprecationHandler func(key, message string)
+	// required lists the keys that must be set after every load.
+	required []string
+	// noSystemEnv hides the variables the config did not set, see owns.
+	noSystemEnv bool
+	// overlays are the configs laid over this one by NewOverlay, highest precedence first.
+	overlays []*EnvLoader
+
+	// envState is shared with the views returned by GetSub.
+	*envState
+}
+
+type envState struct {
+	mu sync.Mutex
+	// loaded holds the values exported from the env files by the last read, so that a reload can tell
+	// them apart from variables that were set by other means.
+	loaded map[string]string
+	// pinned holds the names of the variables set at runtime, such as with Set or LoadFlags, which the env
+	// files never override.
+	pinned map[string]bool
+	// shadowed holds the values that loaded keys replaced, so they come back when a file drops the key.
+	shadowed map[string]string
+	// computing holds a channel for each variable GetOrSet is computing, closed once it is done.
+	computing map[string]chan struct{}
+	// callbacks receive the names of the changed environment variables.
+	callbacks []*changeCallback
+	secrets   map[string]bool
+
+	// defaults, aliases, deprecated and owned are keyed by variable name and have their own lock, since
+	// lookups happen under mu. aliases map to the variable name of the key they stand for, and deprecated to
+	// the message still to be reported. owned holds the variables set through the config, which are the
+	// only ones it reads under WithoutSystemEnv.
+	keysMu     sync.RWMutex
+	defaults   map[string]string
+	aliases    map[string]string
+	deprecated map[string]string
+	owned      map[string]bool
+
+	autoReloadMu   sync.Mutex
+	autoReloadStop chan struct{}
+	autoReloadDone chan struct{}
 }
 
 type logger interface {
@@ -25,58 +104,354 @@ type logger interface {
 
 var configInstance Config
 
-func NewEnvFile(configFolder string, logger logger) Config {
-	conf := &EnvLoader{logger: logger}
-	conf.read(configFolder)
+func NewEnvFile(configFolder string, logger logger, opts ...Option) Config {
+	conf, _ := NewEnvFileWithError(configFolder, logger, opts...)
+	return conf
+}
+
+// NewEnvFileWithError is like NewEnvFile, but it also returns an error when an env file exists and cannot
+// be read or parsed, or when a key given to WithRequiredKeys is not set. Missing files are not an error.
+// The returned config holds whatever did load.
+func NewEnvFileWithError(configFolder string, logger logger, opts ...Option) (Config, error) {
+	conf := &EnvLoader{logger: logger, folder: configFolder, envState: &envState{}}
+	for _, opt := range opts {
+		opt(conf)
+	}
+
+	if conf.envProfile {
+		conf.profile = conf.env()
+	}
+
+	if conf.profile != "" {
+		conf.profilePrefix = conf.envName(conf.profile) + "."
+	}
+
+	values, err := conf.read()
+	conf.apply(values)
 	configInstance = conf
-	return configInstance
+	return configInstance, errors.Join(err, conf.Require(conf.required...))
 }
 
-func (e *EnvLoader) read(folder string) {
-	var (
-		defaultFile  = folder + defaultFileName
-		overrideFile = folder + defaultOverrideFileName
-		env          = e.Get("APP_ENV")
-	)
+// Reload reads the env files again and exports the result. Keys that were loaded from the files before
+// but are no longer present are unset. If a file exists but cannot be parsed, nothing is changed and
+// the error is returned.
+func (e *EnvLoader) Reload() error {
+	return e.ReloadContext(context.Background())
+}
+
+// ReloadContext is like Reload, but gives up with the context's error if ctx is done before the new values
+// are applied, leaving the previous config in place.
+func (e *EnvLoader) ReloadContext(ctx context.Context) error {
+	_, err := e.reload(ctx)
+	return err
+}
+
+// ReloadIfChanged is like Reload, but also reports whether any value changed. Values that are unchanged are
+// left alone, and OnChange callbacks only run when it returns true.
+func (e *EnvLoader) ReloadIfChanged() (bool, error) {
+	changed, err := e.reload(context.Background())
+	return len(changed) > 0, err
+}
+
+func (e *EnvLoader) reload(ctx context.Context) ([]string, error) {
+	changed, err := e.reloadValues(ctx)
+	if e.observer != nil {
+		e.observer.OnReload(err)
+	}
+
+	return changed, err
+}
+
+func (e *EnvLoader) reloadValues(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+
+	values, err := e.read()
+	if err == nil {
+		err = ctx.Err()
+	}
 
-	err := godotenv.Load(defaultFile)
 	if err != nil {
-		e.logger.Warnf("Failed to load config from file: %v, Err: %v", defaultFile, err)
-	} else {
-		e.logger.Infof("Loaded config from file: %v", defaultFile)
+		e.mu.Unlock()
+		return nil, err
 	}
 
-	switch env {
+	changed := e.apply(values)
+	e.mu.Unlock()
+
+	e.notify(changed)
+
+	return changed, e.Require(e.required...)
+}
+
+type envFile struct {
+	path string
+	// optional files are only reported at debug level when they cannot be loaded.
+	optional bool
+}
+
+// files returns the env files in load order. Variables set outside the config files take precedence over
+// the first file, and every later file overrides the ones before it.
+func (e *EnvLoader) files() []envFile {
+	if len(e.envFiles) > 0 {
+		files := make([]envFile, len(e.envFiles))
+		for i, name := range e.envFiles {
+			files[i] = envFile{path: e.path(name)}
+		}
+
+		return files
+	}
+
+	if e.dotenvNaming {
+		return e.dotenvFiles()
+	}
+
+	files := []envFile{{path: e.path(defaultFileName)}}
+
+	switch env := e.env(); env {
 	case "":
 		// If 'APP_ENV' is not set, then GoFr will read '.env' from configs directory, and then it will be overwritten
 		// by configs present in file '.local.env'
-		err = godotenv.Overload(overrideFile)
-		if err != nil {
-			e.logger.Debugf("Failed to load config from file: %v, Err: %v", overrideFile, err)
-		} else {
-			e.logger.Infof("Loaded config from file: %v", overrideFile)
-		}
+		files = append(files, envFile{path: e.path(defaultOverrideFileName), optional: true})
 
 	default:
 		// If 'APP_ENV' is set to x, then GoFr will read '.env' from configs directory, and then it will be overwritten
 		// by configs present in file '.x.env'
-		overrideFile = fmt.Sprintf("%s/.%s.env", folder, env)
+		files = append(files, envFile{path: e.path(fmt.Sprintf(".%s.env", env))})
+	}
+
+	return files
+}
+
+// dotenvFiles returns the files of the dotenv convention used by WithDotenvNaming: '.env', '.env.{env}',
+// '.env.local' and '.env.{env}.local', the ones naming the environment only when one is active.
+func (e *EnvLoader) dotenvFiles() []envFile {
+	env := e.env()
+
+	files := []envFile{{path: e.path(defaultFileName)}}
+	if env != "" {
+		files = append(files, envFile{path: e.path(defaultFileName + "." + env), optional: true})
+	}
+
+	files = append(files, envFile{path: e.path(defaultFileName + ".local"), optional: true})
+	if env != "" {
+		files = append(files, envFile{path: e.path(defaultFileName + "." + env + ".local"), optional: true})
+	}
+
+	return files
+}
+
+// path resolves an env file name against the config folder. Absolute names are used as they are.
+func (e *EnvLoader) path(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+
+	return filepath.Join(e.folder, name)
+}
+
+// env returns the active environment, falling back to the default environment when the variable is unset.
+func (e *EnvLoader) env() string {
+	name := e.envVar
+	if name == "" {
+		name = defaultEnvVar
+	}
 
-		err = godotenv.Overload(overrideFile)
+	if env := os.Getenv(name); env != "" {
+		return env
+	}
+
+	return e.defaultEnv
+}
+
+// LoadEnvFile merges the env file at path into the config, overriding variables that are already set.
+// Like values given to Set, its values survive Reload. A missing file is skipped, while a file that cannot
+// be read or parsed returns an error. With WithFS, path is looked up in that file system first.
+func (e *EnvLoader) LoadEnvFile(path string) error {
+	values, err := e.readEnvFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return e.merge(values)
+}
+
+func (e *EnvLoader) read() (map[string]string, error) {
+	var (
+		values = make(map[string]string)
+		errs   []error
+	)
+
+	for i, file := range e.files() {
+		fileValues, err := e.readEnvFile(file.path)
 		if err != nil {
-			e.logger.Warnf("Failed to load config from file: %v, Err: %v", overrideFile, err)
+			if file.optional {
+				e.logger.Debugf("Failed to load config from file: %v, Err: %v", file.path, err)
+			} else {
+				e.logger.Warnf("Failed to load config from file: %v, Err: %v", file.path, err)
+			}
+
+			errs = appendReadError(errs, err)
+
+			continue
+		}
+
+		e.logger.Infof("Loaded config from file: %v", file.path)
+
+		for k, v := range fileValues {
+			if e.pinned[k] {
+				continue
+			}
+
+			// A key this config loaded before may be re-applied, unless loading it replaced a variable
+			// that this file must not override
+			_, loaded := e.loaded[k]
+			_, shadowed := e.shadowed[k]
+
+			if e.filesOverride(i) || !isSet(k) || loaded && !shadowed {
+				values[k] = v
+			}
+		}
+	}
+
+	if e.templates {
+		errs = append(errs, e.expandTemplates(values))
+	}
+
+	return values, errors.Join(errs...)
+}
+
+// filesOverride reports whether the i-th env file overrides variables that are already set.
+func (e *EnvLoader) filesOverride(i int) bool {
+	if e.noSystemEnv {
+		return true
+	}
+
+	switch e.systemEnvPriority {
+	case SystemEnvHigh:
+		return false
+	case SystemEnvLow:
+		return true
+	default:
+		return i > 0
+	}
+}
+
+// readEnvFile parses the env file at path, looking in the file system given to WithFS first if there is one.
+func (e *EnvLoader) readEnvFile(path string) (map[string]string, error) {
+	if e.fsys == nil {
+		return godotenv.Read(path)
+	}
+
+	name := filepath.ToSlash(filepath.Clean(path))
+	if !fs.ValidPath(name) {
+		return godotenv.Read(path)
+	}
+
+	f, err := e.fsys.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return godotenv.Read(path)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return godotenv.Parse(f)
+}
+
+func isSet(key string) bool {
+	_, ok := os.LookupEnv(key)
+	return ok
+}
+
+// appendReadError keeps err unless it reports a missing file, since every env file is optional.
+func appendReadError(errs []error, err error) []error {
+	if err == nil || errors.Is(err, fs.ErrNotExist) {
+		return errs
+	}
+
+	return append(errs, err)
+}
+
+// apply exports values to the environment. Keys from the previous read that are gone are unset, or set
+// back to the value they replaced if they were already set when first loaded. It returns the sorted list
+// of keys whose value changed.
+func (e *EnvLoader) apply(values map[string]string) []string {
+	var changed []string
+
+	for k, v := range values {
+		e.own(k)
+
+		old, ok := os.LookupEnv(k)
+		if _, loaded := e.loaded[k]; ok && !loaded {
+			if e.shadowed == nil {
+				e.shadowed = make(map[string]string)
+			}
+
+			e.shadowed[k] = old
+		}
+
+		if ok && old == v {
+			continue
+		}
+
+		if err := os.Setenv(k, v); err != nil {
+			e.logger.Warnf("Failed to set config %v, Err: %v", k, err)
+			continue
+		}
+
+		changed = append(changed, k)
+	}
+
+	for k := range e.loaded {
+		if _, ok := values[k]; ok {
+			continue
+		}
+
+		old, ok := e.shadowed[k]
+		delete(e.shadowed, k)
+		e.disown(k)
+
+		if !ok {
+			os.Unsetenv(k)
+		} else if old != e.loaded[k] {
+			os.Setenv(k, old)
 		} else {
-			e.logger.Infof("Loaded config from file: %v", overrideFile)
+			continue
 		}
+
+		changed = append(changed, k)
 	}
+
+	e.loaded = values
+	sort.Strings(changed)
+
+	return changed
 }
 
 func (e *EnvLoader) Get(key string) string {
-	return os.Getenv(key)
+	e.warnDeprecated(key)
+
+	val, ok := e.lookup(key)
+	if e.observer != nil {
+		e.observer.OnGet(key, ok)
+	}
+
+	return val
 }
 
 func (e *EnvLoader) GetOrDefault(key, defaultValue string) string {
-	if val := os.Getenv(key); val != "" {
+	if val := e.Get(key); val != "" {
 		return val
 	}
 
@@ -84,17 +459,804 @@ func (e *EnvLoader) GetOrDefault(key, defaultValue string) string {
 }
 
 func (e *EnvLoader) GetArray(key string) []string {
+	return e.GetArrayBy(key, defaultSeparator)
+}
+
+func (e *EnvLoader) GetArrayBy(key, sep string) []string {
 	envStr := e.Get(key)
 	if envStr == "" {
 		return nil
 	}
-	strArr := strings.Split(envStr, ",")
+	return splitArray(envStr, sep)
+}
+
+// Set exports value for key and notifies the OnChange callbacks if it changed. The value takes precedence
+// over the env files until Unset, so Reload, Watch and StartAutoReload keep it.
+func (e *EnvLoader) Set(key, value string) error {
+	e.mu.Lock()
+
+	e.pin(e.envName(key))
+
+	if old, ok := e.lookupName(e.envName(key)); ok && old == value {
+		e.mu.Unlock()
+		return nil
+	}
+
+	name := e.envName(key)
+	if err := os.Setenv(name, value); err != nil {
+		e.mu.Unlock()
+		return err
+	}
+
+	e.own(name)
+
+	e.mu.Unlock()
+
+	e.notify([]string{name})
+
+	return nil
+}
+
+// SetDefault registers a value for key that is used only while the key is not set by any other means, so
+// env files, the environment and Set all take precedence over it. Defaults are kept in memory rather than
+// exported, are not touched by Reload, and show up in Keys, GetStringMapString and the other listings.
+// Setting a default does not notify OnChange callbacks.
+func (e *EnvLoader) SetDefault(key, value string) {
+	e.keysMu.Lock()
+	defer e.keysMu.Unlock()
+
+	if e.defaults == nil {
+		e.defaults = make(map[string]string)
+	}
+
+	e.defaults[e.envName(key)] = value
+}
+
+// Alias makes alias read the value of key, so that both names work while a key is being renamed: Get,
+// Has and the typed getters called with alias look up key instead. Aliases are not listed by Keys and
+// the like, and Set and Unset still act on alias itself.
+func (e *EnvLoader) Alias(alias, key string) {
+	e.keysMu.Lock()
+	defer e.keysMu.Unlock()
+
+	if e.aliases == nil {
+		e.aliases = make(map[string]string)
+	}
+
+	e.aliases[e.envName(alias)] = e.envName(key)
+}
+
+// DeprecateKey reports message the first time key is read with Get or a getter built on it, through the
+// handler given to WithDeprecationHandler or else as a warning in the log. Applied to an alias, it flags
+// reads under the old name.
+func (e *EnvLoader) DeprecateKey(key, message string) {
+	e.keysMu.Lock()
+	defer e.keysMu.Unlock()
+
+	if e.deprecated == nil {
+		e.deprecated = make(map[string]string)
+	}
+
+	e.deprecated[e.envName(key)] = message
+}
+
+func (e *EnvLoader) warnDeprecated(key string) {
+	name := e.envName(key)
+
+	e.keysMu.RLock()
+	_, ok := e.deprecated[name]
+	e.keysMu.RUnlock()
+
+	if !ok {
+		return
+	}
+
+	e.keysMu.Lock()
+	message, ok := e.deprecated[name]
+	delete(e.deprecated, name)
+	e.keysMu.Unlock()
+
+	switch {
+	case !ok:
+	case e.deprecationHandler != nil:
+		e.deprecationHandler(key, message)
+	default:
+		e.logger.Warnf("Config key %v is deprecated: %v", key, message)
+	}
+}
+
+// SetMany sets every key in values under a single lock, so it does not interleave with Reload or another
+// Set, and notifies OnChange callbacks once with all the keys that changed. The update is all or nothing:
+// every key and value is checked before anything is set, and if the environment still rejects one, the
+// keys set before it get their previous values back and no callback runs.
+func (e *EnvLoader) SetMany(values map[string]string) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := checkEnv(e.envName(key), values[key]); err != nil {
+			return &ConfigError{Key: key, Err: err}
+		}
+	}
+
+	type previous struct {
+		name, value string
+		set         bool
+	}
+
+	e.mu.Lock()
+
+	var applied []previous
+
+	for _, key := range keys {
+		name, value := e.envName(key), values[key]
+		if cur, ok := e.lookupName(name); ok && cur == value {
+			continue
+		}
+
+		old, set := os.LookupEnv(name)
+		if err := os.Setenv(name, value); err != nil {
+			for i := len(applied) - 1; i >= 0; i-- {
+				if p := applied[i]; p.set {
+					os.Setenv(p.name, p.value)
+				} else {
+					os.Unsetenv(p.name)
+				}
+			}
+
+			e.mu.Unlock()
+
+			return err
+		}
+
+		applied = append(applied, previous{name: name, value: old, set: set})
+	}
+
+	changed := make([]string, len(applied))
+	for i, p := range applied {
+		changed[i] = p.name
+	}
+
+	for _, key := range keys {
+		e.pin(e.envName(key))
+		e.own(e.envName(key))
+	}
+
+	e.mu.Unlock()
+
+	sort.Strings(changed)
+	e.notify(changed)
+
+	return nil
+}
+
+// checkEnv reports why the environment would reject the variable name set to value.
+func checkEnv(name, value string) error {
+	if name == "" || strings.ContainsAny(name, "=\x00") {
+		return fmt.Errorf("%q is not a valid variable name", name)
+	}
+
+	if strings.Contains(value, "\x00") {
+		return errors.New("value contains a NUL byte")
+	}
+
+	return nil
+}
+
+// GetOrSet returns the value of key, or, when it is missing or empty, calls compute and exports its result
+// as Set would. compute runs without the config lock held, so it may read and modify the config; concurrent
+// callers for the same key wait for its result instead of calling compute again. If the key was set while
+// compute ran, that value is kept and returned. Like a value given to Set, the stored value survives Reload.
+func (e *EnvLoader) GetOrSet(key string, compute func() string) string {
+	name := e.envName(key)
+
+	for {
+		e.mu.Lock()
+
+		if val, _ := e.lookup(key); val != "" {
+			e.mu.Unlock()
+			return val
+		}
+
+		if done, ok := e.computing[name]; ok {
+			e.mu.Unlock()
+			<-done
+
+			continue
+		}
+
+		if e.computing == nil {
+			e.computing = make(map[string]chan struct{})
+		}
+
+		done := make(chan struct{})
+		e.computing[name] = done
+		e.mu.Unlock()
+
+		return e.setComputed(key, name, done, compute)
+	}
+}
+
+// setComputed calls compute for GetOrSet and exports its result, then releases the callers waiting on done.
+func (e *EnvLoader) setComputed(key, name string, done chan struct{}, compute func() string) string {
+	defer func() {
+		e.mu.Lock()
+		delete(e.computing, name)
+		e.mu.Unlock()
+
+		close(done)
+	}()
+
+	val := compute()
+
+	e.mu.Lock()
+
+	if cur, _ := e.lookup(key); cur != "" {
+		e.mu.Unlock()
+		return cur
+	}
+
+	if err := os.Setenv(name, val); err != nil {
+		e.mu.Unlock()
+		e.logger.Warnf("Failed to set config %v, Err: %v", key, err)
+
+		return val
+	}
+
+	e.pin(name)
+	e.own(name)
+
+	e.mu.Unlock()
+
+	e.notify([]string{name})
+
+	return val
+}
+
+// Unset removes key from the environment. It does not stop the key from coming back on the next Reload
+// if an env file still provides it, even when it was set with Set or another runtime loader.
+func (e *EnvLoader) Unset(key string) error {
+	e.mu.Lock()
+	names, err := e.unset(key)
+	e.mu.Unlock()
+
+	e.notify(names)
+
+	return err
+}
+
+// unset removes every variable that key is set under and returns their names. It must be called with mu
+// held.
+func (e *EnvLoader) unset(key string) ([]string, error) {
+	// Under WithEnvKeyReplacer the key may also be set under its variable-style name, such as DB__HOST
+	targets := []string{e.envName(key)}
+	if e.keySep != "" {
+		targets = append(targets, e.envStyle(e.envName(key)))
+	}
+
+	var names []string
+
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		if !e.owns(name) {
+			continue
+		}
+
+		for _, target := range targets {
+			if name == target || e.foldCase && strings.EqualFold(name, target) {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+
+	for i, name := range names {
+		if err := os.Unsetenv(name); err != nil {
+			return names[:i], err
+		}
+
+		delete(e.pinned, name)
+		e.disown(name)
+	}
+
+	return names, nil
+}
+
+func (e *EnvLoader) Has(key string) bool {
+	_, ok := e.lookup(key)
+	return ok
+}
+
+// HasPrefix reports whether any key is set under "<prefix>.", such as tls.cert for the prefix tls.
+func (e *EnvLoader) HasPrefix(prefix string) bool {
+	return len(e.GetStringMapString(prefix)) > 0
+}
+
+// GetSub returns a view of the keys under "<prefix>." with the prefix stripped, so GetSub("db").Get("host")
+// reads db.host. The view reads through to the environment rather than a snapshot, and it shares the
+// parent's change callbacks and reload state.
+func (e *EnvLoader) GetSub(prefix string) Config {
+	sub := *e
+	sub.prefix = e.envName(prefix) + "."
+
+	if e.profilePrefix != "" {
+		sub.profilePrefix = e.profilePrefix + e.canonical(prefix) + "."
+	}
+
+	sub.overlays = make([]*EnvLoader, len(e.overlays))
+	for i, o := range e.overlays {
+		sub.overlays[i] = o.GetSub(prefix).(*EnvLoader)
+	}
+
+	return &sub
+}
+
+// GetStringMapString returns the keys under "<key>." with the prefix stripped. Nothing is cached: the map
+// is built from the environment on every call, so it reflects a preceding Set or Reload.
+func (e *EnvLoader) GetStringMapString(key string) map[string]string {
+	var (
+		prefix = e.canonical(key) + "."
+		result = make(map[string]string)
+	)
+
+	for k, v := range e.environ() {
+		if strings.HasPrefix(k, prefix) {
+			result[strings.TrimPrefix(k, prefix)] = v
+		}
+	}
+
+	return result
+}
+
+// GetOrderedMapKeys returns the keys of GetStringMapString(key) in sorted order, for iterating over that
+// map deterministically.
+func (e *EnvLoader) GetOrderedMapKeys(key string) []string {
+	m := e.GetStringMapString(key)
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// GetStringMapArray returns the keys under "<key>." with the prefix stripped, each value split like GetArray.
+func (e *EnvLoader) GetStringMapArray(key string) map[string][]string {
+	result := make(map[string][]string)
+	for k, v := range e.GetStringMapString(key) {
+		if v == "" {
+			result[k] = nil
+			continue
+		}
+
+		result[k] = splitArray(v, defaultSeparator)
+	}
+
+	return result
+}
+
+// GetNestedMap returns the keys under "<key>." as nested maps to any depth, so with db.primary.host set,
+// GetNestedMap("db")["primary"].(map[string]any)["host"] is its value. Leaves are strings, and when a key
+// is both a value and a parent of other keys the nested keys win.
+func (e *EnvLoader) GetNestedMap(key string) map[string]any {
+	return unflatten(e.GetStringMapString(key), func(s string) any {
+		return s
+	})
+}
+
+// GetObjectArray returns the objects of an array flattened into indexed keys, so with servers.0.host and
+// servers.1.host set, GetObjectArray("servers")[1]["host"] is the second host. Keys nested deeper keep their
+// dots. Indices are read from 0 and stop at the first missing one, so anything after a gap is ignored.
+func (e *EnvLoader) GetObjectArray(key string) []map[string]string {
+	objects := make(map[string]map[string]string)
+	for k, v := range e.GetStringMapString(key) {
+		i, field, ok := strings.Cut(k, ".")
+		if !ok {
+			continue
+		}
+
+		if objects[i] == nil {
+			objects[i] = make(map[string]string)
+		}
+
+		objects[i][field] = v
+	}
+
+	var result []map[string]string
+
+	for i := 0; objects[strconv.Itoa(i)] != nil; i++ {
+		result = append(result, objects[strconv.Itoa(i)])
+	}
+
+	return result
+}
+
+// Keys returns the sorted keys visible through the config.
+func (e *EnvLoader) Keys() []string {
+	return e.KeysWithPrefix("")
+}
+
+// Range calls fn for each key visible through the config and its value, in key order, until fn returns
+// false. It iterates over a copy taken when it is called and holds no lock while fn runs, so fn may call
+// back into the config, including Set and Reload; such changes are not seen by the rest of the iteration.
+func (e *EnvLoader) Range(fn func(key, value string) bool) {
+	env := e.environ()
+
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if !fn(k, env[k]) {
+			return
+		}
+	}
+}
+
+// KeysWithPrefix returns the sorted keys that start with prefix.
+func (e *EnvLoader) KeysWithPrefix(prefix string) []string {
+	prefix = e.canonical(prefix)
+
+	var keys []string
+
+	for k := range e.environ() {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// lookup reads key from the environment, preferring the configs laid over this one and then the key under
+// the active profile if there is one, and falling back to the default set with SetDefault.
+func (e *EnvLoader) lookup(key string) (string, bool) {
+	for _, o := range e.overlays {
+		if val, ok := o.lookup(key); ok {
+			return val, true
+		}
+	}
+
+	key = e.resolveAlias(key)
+
+	if e.profilePrefix != "" {
+		if val, ok := e.lookupName(e.profilePrefix + e.canonical(key)); ok {
+			return val, true
+		}
+	}
+
+	if val, ok := e.lookupName(e.envName(key)); ok {
+		return val, true
+	}
+
+	if e.keySep != "" {
+		if val, ok := e.lookupName(e.envStyle(e.envName(key))); ok {
+			return val, true
+		}
+	}
+
+	return e.lookupDefault(key)
+}
+
+// resolveAlias returns the key that key is an alias of, or key itself.
+func (e *EnvLoader) resolveAlias(key string) string {
+	e.keysMu.RLock()
+	target, ok := e.aliases[e.envName(key)]
+	e.keysMu.RUnlock()
+
+	if !ok {
+		return key
+	}
+
+	if k, ok := e.trimPrefix(target, e.prefix); ok {
+		return k
+	}
+
+	return key
+}
+
+func (e *EnvLoader) lookupDefault(key string) (string, bool) {
+	e.keysMu.RLock()
+	defer e.keysMu.RUnlock()
+
+	val, ok := e.defaults[e.envName(key)]
+
+	return val, ok
+}
+
+func (e *EnvLoader) lookupName(name string) (string, bool) {
+	if val, ok := os.LookupEnv(name); ok && e.owns(name) {
+		return val, true
+	}
+
+	if !e.foldCase {
+		return "", false
+	}
+
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok && strings.EqualFold(k, name) && e.owns(k) {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// owns reports whether the variable name is visible through the config, which under WithoutSystemEnv is
+// only the case for the variables it set.
+func (e *EnvLoader) owns(name string) bool {
+	if !e.noSystemEnv {
+		return true
+	}
+
+	e.keysMu.RLock()
+	defer e.keysMu.RUnlock()
+
+	return e.owned[name]
+}
+
+// own records that the config set the variable name, for WithoutSystemEnv.
+func (e *EnvLoader) own(name string) {
+	if !e.noSystemEnv {
+		return
+	}
+
+	e.keysMu.Lock()
+	defer e.keysMu.Unlock()
+
+	if e.owned == nil {
+		e.owned = make(map[string]bool)
+	}
+
+	e.owned[name] = true
+}
+
+// disown records that the config unset the variable name.
+func (e *EnvLoader) disown(name string) {
+	if !e.noSystemEnv {
+		return
+	}
+
+	e.keysMu.Lock()
+	delete(e.owned, name)
+	e.keysMu.Unlock()
+}
+
+func (e *EnvLoader) envName(key string) string {
+	return e.prefix + e.canonical(key)
+}
+
+func (e *EnvLoader) canonical(key string) string {
+	if e.foldCase {
+		return strings.ToUpper(key)
+	}
+
+	return key
+}
+
+// envStyle converts a key to the variable name it is written as under WithEnvKeyReplacer.
+func (e *EnvLoader) envStyle(name string) string {
+	name = strings.ReplaceAll(name, ".", e.keySep)
+	if e.keyLower {
+		name = strings.ToUpper(name)
+	}
+
+	return name
+}
+
+// replacedKey maps a variable name written with WithEnvKeyReplacer, such as DB__HOST, back to its key.
+// Names without the separator are otherwise left to the plain lookup.
+func (e *EnvLoader) replacedKey(name string) (string, bool) {
+	if e.keySep == "" {
+		return "", false
+	}
+
+	// Below a dotted prefix, as in the views returned by GetSub, every name is written with the replacer
+	k, ok := strings.CutPrefix(name, e.envStyle(e.prefix))
+	if !ok || !strings.Contains(k, e.keySep) && !strings.HasSuffix(e.prefix, ".") {
+		return "", false
+	}
+
+	k = strings.ReplaceAll(k, e.keySep, ".")
+	if e.keyLower {
+		k = strings.ToLower(k)
+	}
+
+	return k, true
+}
+
+// environ returns the variables visible through the config, keyed without the prefix.
+func (e *EnvLoader) environ() map[string]string {
+	var (
+		env      = make(map[string]string)
+		profile  = make(map[string]string)
+		replaced = make(map[string]string)
+	)
+
+	for _, kv := range os.Environ() {
+		name, v, _ := strings.Cut(kv, "=")
+		if !e.owns(name) {
+			continue
+		}
+
+		if k, ok := e.trimPrefix(name, e.profilePrefix); ok && e.profilePrefix != "" {
+			addCanonical(profile, k, e.canonical(k), v)
+		}
+
+		// A name written with the key replacer is listed only under the dotted key it stands for
+		if k, ok := e.replacedKey(name); ok {
+			replaced[k] = v
+		} else if k, ok := e.trimPrefix(name, e.prefix); ok {
+			addCanonical(env, k, e.canonical(k), v)
+		}
+	}
+
+	// A key set under its own name wins over one written with the key replacer
+	for k, v := range replaced {
+		if _, exists := env[k]; !exists {
+			env[k] = v
+		}
+	}
+
+	for k, v := range profile {
+		env[k] = v
+	}
+
+	// Lowest precedence first, so the overlays that win are written last
+	for i := len(e.overlays) - 1; i >= 0; i-- {
+		for k, v := range e.overlays[i].environ() {
+			env[k] = v
+		}
+	}
+
+	e.keysMu.RLock()
+	defer e.keysMu.RUnlock()
+
+	for name, v := range e.defaults {
+		if k, ok := e.trimPrefix(name, e.prefix); ok {
+			if _, exists := env[e.canonical(k)]; !exists {
+				env[e.canonical(k)] = v
+			}
+		}
+	}
+
+	return env
+}
+
+// addCanonical stores v under key. When keys differ only by case, the one already in canonical form wins.
+func addCanonical(env map[string]string, k, key, v string) {
+	if _, exists := env[key]; !exists || k == key {
+		env[key] = v
+	}
+}
+
+// configKeys maps environment variable names to config keys, dropping the ones outside the prefix.
+func (e *EnvLoader) configKeys(names []string) []string {
+	if e.prefix == "" && !e.foldCase && e.profilePrefix == "" && e.keySep == "" && len(e.overlays) == 0 {
+		return names
+	}
+
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		if k, ok := e.trimPrefix(name, e.prefix); ok {
+			keys = append(keys, e.canonical(k))
+		}
+
+		if k, ok := e.trimPrefix(name, e.profilePrefix); ok && e.profilePrefix != "" {
+			keys = append(keys, e.canonical(k))
+		}
+
+		if k, ok := e.replacedKey(name); ok {
+			keys = append(keys, k)
+		}
+	}
+
+	for _, o := range e.overlays {
+		keys = append(keys, o.configKeys(names)...)
+	}
+
+	return keys
+}
+
+func (e *EnvLoader) trimPrefix(name, prefix string) (string, bool) {
+	if len(name) < len(prefix) {
+		return "", false
+	}
+
+	if name[:len(prefix)] == prefix || e.foldCase && strings.EqualFold(name[:len(prefix)], prefix) {
+		return name[len(prefix):], true
+	}
+
+	return "", false
+}
+
+// splitArray splits s on sep and trims the elements. A value written as a JSON array, such as ["a","b"],
+// is decoded instead, and a value with double quotes is read as a CSV record, so a,"b,c" has two elements.
+// Either falls back to plain splitting if the value does not parse.
+func splitArray(s, sep string) []string {
+	if items, ok := jsonArray(s); ok {
+		return items
+	}
+
+	if items, ok := csvRecord(s, sep); ok {
+		return items
+	}
+
+	strArr := strings.Split(s, sep)
 	for i, s := range strArr {
 		strArr[i] = strings.TrimSpace(s)
 	}
 	return strArr
 }
 
+func csvRecord(s, sep string) ([]string, bool) {
+	comma := []rune(sep)
+	if !strings.Contains(s, `"`) || len(comma) != 1 {
+		return nil, false
+	}
+
+	r := csv.NewReader(strings.NewReader(s))
+	r.Comma = comma[0]
+	r.TrimLeadingSpace = true
+
+	items, err := r.Read()
+	if err != nil {
+		return nil, false
+	}
+
+	// A second record means the value spans lines, which plain splitting keeps together
+	if _, err := r.Read(); err != io.EOF {
+		return nil, false
+	}
+
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+
+	return items, true
+}
+
+func jsonArray(s string) ([]string, bool) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return nil, false
+	}
+
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+
+	var raw []any
+	if err := dec.Decode(&raw); err != nil {
+		return nil, false
+	}
+
+	items := make([]string, len(raw))
+	for i, v := range raw {
+		switch v := v.(type) {
+		case nil:
+		case string:
+			items[i] = strings.TrimSpace(v)
+		default:
+			b, _ := json.Marshal(v)
+			items[i] = string(b)
+		}
+	}
+
+	return items, true
+}
+
 func Get(key string) string {
 	return configInstance.Get(key)
 }
@@ -106,3 +1268,71 @@ func GetOrDefault(key, defaultValue string) string {
 func GetArray(key string) []string {
 	return configInstance.GetArray(key)
 }
+
+func DeprecateKey(key, message string) {
+	configInstance.DeprecateKey(key, message)
+}
+
+func Alias(alias, key string) {
+	configInstance.Alias(alias, key)
+}
+
+func SetDefault(key, value string) {
+	configInstance.SetDefault(key, value)
+}
+
+func GetOrSet(key string, compute func() string) string {
+	return configInstance.GetOrSet(key, compute)
+}
+
+func LoadEnvFile(path string) error {
+	return configInstance.LoadEnvFile(path)
+}
+
+func Has(key string) bool {
+	return configInstance.Has(key)
+}
+
+func HasPrefix(prefix string) bool {
+	return configInstance.HasPrefix(prefix)
+}
+
+func GetArrayBy(key, sep string) []string {
+	return configInstance.GetArrayBy(key, sep)
+}
+
+func GetOrderedMapKeys(key string) []string {
+	return configInstance.GetOrderedMapKeys(key)
+}
+
+func GetStringMapArray(key string) map[string][]string {
+	return configInstance.GetStringMapArray(key)
+}
+
+func GetObjectArray(key string) []map[string]string {
+	return configInstance.GetObjectArray(key)
+}
+
+func GetNestedMap(key string) map[string]any {
+	return configInstance.GetNestedMap(key)
+}
+
+func Keys() []string {
+	return configInstance.Keys()
+}
+
+func Range(fn func(key, value string) bool) {
+	configInstance.Range(fn)
+}
+
+func KeysWithPrefix(prefix string) []string {
+	return configInstance.KeysWithPrefix(prefix)
+}
+
+func GetStringMapString(key string) map[string]string {
+	return configInstance.GetStringMapString(key)
+}
+
+func Unmarshal(target any) error {
+	return configInstance.Unmarshal(target)
+}
@@ -0,0 +1,553 @@
+package cfgo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testLogger struct{}
+
+func (testLogger) Infof(string, ...interface{})  {}
+func (testLogger) Warnf(string, ...interface{})  {}
+func (testLogger) Debugf(string, ...interface{}) {}
+
+// unsetEnv unsets key for the duration of the test.
+func unsetEnv(t *testing.T, key string) {
+	t.Helper()
+
+	t.Setenv(key, "")
+	os.Unsetenv(key)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadRestoresSystemValueWhenOverrideIsDropped(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("APP_ENV", "")
+	t.Setenv("CFGO_TEST_K", "sys")
+
+	writeFile(t, filepath.Join(dir, ".env"), "CFGO_TEST_K=base\n")
+	writeFile(t, filepath.Join(dir, ".local.env"), "CFGO_TEST_K=local\n")
+
+	conf := NewEnvFile(dir, testLogger{})
+	if got := conf.Get("CFGO_TEST_K"); got != "local" {
+		t.Fatalf("Get() = %q, want %q", got, "local")
+	}
+
+	writeFile(t, filepath.Join(dir, ".local.env"), "")
+
+	if err := conf.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := conf.Get("CFGO_TEST_K"); got != "sys" {
+		t.Errorf("after reload Get() = %q, want %q", got, "sys")
+	}
+}
+
+func TestUnsetRemovesReplacedName(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	t.Setenv("DB__HOST", "x")
+
+	conf := NewEnvFile(t.TempDir(), testLogger{}, WithEnvKeyReplacer("__", true))
+
+	if err := conf.Unset("db.host"); err != nil {
+		t.Fatal(err)
+	}
+
+	if conf.Has("db.host") {
+		t.Errorf("Has() = true after Unset, want false")
+	}
+}
+
+func TestGetOrSetComputeMayUseConfig(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	unsetEnv(t, "CFGO_TEST_TOKEN")
+
+	conf := NewEnvFile(t.TempDir(), testLogger{})
+
+	done := make(chan string)
+	go func() {
+		done <- conf.GetOrSet("CFGO_TEST_TOKEN", func() string {
+			_ = conf.Dump()
+			return "computed"
+		})
+	}()
+
+	select {
+	case got := <-done:
+		if got != "computed" {
+			t.Errorf("GetOrSet() = %q, want %q", got, "computed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetOrSet deadlocked when compute read the config")
+	}
+}
+
+func TestGetOrSetComputesOnce(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	unsetEnv(t, "CFGO_TEST_ONCE")
+
+	conf := NewEnvFile(t.TempDir(), testLogger{})
+
+	var (
+		calls atomic.Int32
+		wg    sync.WaitGroup
+	)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			got := conf.GetOrSet("CFGO_TEST_ONCE", func() string {
+				calls.Add(1)
+				time.Sleep(10 * time.Millisecond)
+
+				return "v"
+			})
+			if got != "v" {
+				t.Errorf("GetOrSet() = %q, want %q", got, "v")
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("compute called %d times, want 1", n)
+	}
+}
+
+func TestSplitArray(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		sep  string
+		want []string
+	}{
+		{"plain", "a,b,c", ",", []string{"a", "b", "c"}},
+		{"plain trims spaces", " a , b ,c ", ",", []string{"a", "b", "c"}},
+		{"plain single", "a", ",", []string{"a"}},
+		{"plain empty elements", ",", ",", []string{"", ""}},
+		{"plain other separator", "a::b", "::", []string{"a", "b"}},
+		{"json strings", `["a","b,c"]`, ",", []string{"a", "b,c"}},
+		{"json with spaces", ` [ "a" , "b" ] `, ",", []string{"a", "b"}},
+		{"json scalars", `["a", 1, 2.5, true, null]`, ",", []string{"a", "1", "2.5", "true", ""}},
+		{"json nested", `[[1,2],{"k":"v"}]`, ",", []string{"[1,2]", `{"k":"v"}`}},
+		{"json empty", `[]`, ",", []string{}},
+		{"invalid json splits plainly", "[a,b]", ",", []string{"[a", "b]"}},
+		{"unterminated json splits plainly", `["a","b"`, ",", []string{`["a"`, `"b"`}},
+		{"quoted csv field", `a,"b,c",d`, ",", []string{"a", "b,c", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitArray(tt.in, tt.sep); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitArray(%q, %q) = %q, want %q", tt.in, tt.sep, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVRecord(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		sep    string
+		want   []string
+		wantOK bool
+	}{
+		{"quoted separator", `a,"b,c",d`, ",", []string{"a", "b,c", "d"}, true},
+		{"escaped quote", `a,"say ""hi""",d`, ",", []string{"a", `say "hi"`, "d"}, true},
+		{"leading space before quote", `a, "b,c"`, ",", []string{"a", "b,c"}, true},
+		{"quoted newline", "a,\"b\nc\"", ",", []string{"a", "b\nc"}, true},
+		{"semicolon", `a;"b;c";d`, ";", []string{"a", "b;c", "d"}, true},
+		{"pipe", `a|"b|c"`, "|", []string{"a", "b|c"}, true},
+		{"unbalanced quote", `a,"b`, ",", nil, false},
+		{"bare quote in field", `a,b"c`, ",", nil, false},
+		{"second record", "a,\"b\"\nc,d", ",", nil, false},
+		{"no quotes", "a,b", ",", nil, false},
+		{"multi-character separator", `a::"b"`, "::", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := csvRecord(tt.in, tt.sep)
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("csvRecord(%q, %q) = %q, %v, want %q, %v", tt.in, tt.sep, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	// A value csvRecord rejects still splits plainly
+	if got, want := splitArray(`a,"b`, ","), []string{"a", `"b`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("splitArray() = %q, want %q", got, want)
+	}
+}
+
+func TestSetIsReflectedInStringMap(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	t.Setenv("db.host", "old")
+	t.Setenv("db.port", "5432")
+
+	conf := NewEnvFile(t.TempDir(), testLogger{})
+
+	if got := conf.GetStringMapString("db")["host"]; got != "old" {
+		t.Fatalf("GetStringMapString()[host] = %q, want %q", got, "old")
+	}
+
+	if err := conf.Set("db.host", "new"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"host": "new", "port": "5432"}
+	if got := conf.GetStringMapString("db"); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetStringMapString() = %v, want %v", got, want)
+	}
+}
+
+func TestEnvFilesResolveAgainstConfigFolder(t *testing.T) {
+	var (
+		dir      = t.TempDir()
+		otherDir = t.TempDir()
+		absPath  = filepath.Join(t.TempDir(), "abs.env")
+	)
+
+	t.Setenv("APP_ENV", "")
+	unsetEnv(t, "CFGO_TEST_REL")
+	unsetEnv(t, "CFGO_TEST_ABS")
+
+	writeFile(t, filepath.Join(dir, "app.env"), "CFGO_TEST_REL=folder\n")
+	writeFile(t, filepath.Join(otherDir, "app.env"), "CFGO_TEST_REL=cwd\n")
+	writeFile(t, absPath, "CFGO_TEST_ABS=abs\n")
+
+	// Run from another directory holding a file of the same name, which must not be read
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chdir(otherDir); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	conf := NewEnvFile(dir, testLogger{}, WithEnvFiles("app.env", absPath))
+
+	if got := conf.Get("CFGO_TEST_REL"); got != "folder" {
+		t.Errorf("Get(CFGO_TEST_REL) = %q, want %q", got, "folder")
+	}
+
+	if got := conf.Get("CFGO_TEST_ABS"); got != "abs" {
+		t.Errorf("Get(CFGO_TEST_ABS) = %q, want %q", got, "abs")
+	}
+}
+
+func TestFailedReloadKeepsPreviousValues(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("APP_ENV", "")
+	unsetEnv(t, "CFGO_TEST_A")
+	unsetEnv(t, "CFGO_TEST_B")
+
+	writeFile(t, filepath.Join(dir, ".env"), "CFGO_TEST_A=1\n")
+	writeFile(t, filepath.Join(dir, ".local.env"), "CFGO_TEST_B=2\n")
+
+	conf := NewEnvFile(dir, testLogger{})
+
+	writeFile(t, filepath.Join(dir, ".env"), "CFGO_TEST_A=changed\n")
+	writeFile(t, filepath.Join(dir, ".local.env"), "CFGO_TEST_B=\"unterminated\n")
+
+	if err := conf.Reload(); err == nil {
+		t.Fatal("Reload() of an unparsable file returned no error")
+	}
+
+	if got := conf.Get("CFGO_TEST_A"); got != "1" {
+		t.Errorf("Get(CFGO_TEST_A) = %q, want %q", got, "1")
+	}
+
+	if got := conf.Get("CFGO_TEST_B"); got != "2" {
+		t.Errorf("Get(CFGO_TEST_B) = %q, want %q", got, "2")
+	}
+}
+
+// TestEnvFileSyntax pins down how the env files are parsed, which is left to godotenv.
+func TestEnvFileSyntax(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("APP_ENV", "")
+
+	content := `GREETING="  hello = world  "
+SQ='  a b  '
+EMPTY=""
+TLS_KEY="-----BEGIN
+abc
+-----END"
+AFTER=1
+ESCAPED="a\nb"
+PORT=8080 # the http port
+COLOR=#ffffff
+PASS="a#b"
+export DATABASE_URL=postgres://x
+export=keep
+BASE=/srv
+BRACED=${BASE}/app
+BARE=$BASE/bin
+SINGLE='$BASE'
+DOLLAR="\$BASE"
+` + "TRIMMED=   plain   \n"
+	writeFile(t, filepath.Join(dir, ".env"), content)
+
+	want := map[string]string{
+		"GREETING":     "  hello = world  ",
+		"SQ":           "  a b  ",
+		"EMPTY":        "",
+		"TRIMMED":      "plain",
+		"TLS_KEY":      "-----BEGIN\nabc\n-----END",
+		"AFTER":        "1",
+		"ESCAPED":      "a\nb",
+		"PORT":         "8080",
+		"COLOR":        "#ffffff",
+		"PASS":         "a#b",
+		"DATABASE_URL": "postgres://x",
+		"export":       "keep",
+		"BASE":         "/srv",
+		"BRACED":       "/srv/app",
+		"BARE":         "/srv/bin",
+		"SINGLE":       "$BASE",
+		"DOLLAR":       "$BASE",
+	}
+
+	for key := range want {
+		unsetEnv(t, key)
+	}
+
+	conf := NewEnvFile(dir, testLogger{})
+
+	for key, val := range want {
+		if got, ok := os.LookupEnv(key); !ok || got != val {
+			t.Errorf("%s = %q (set %v), want %q", key, got, ok, val)
+		}
+	}
+
+	if !conf.Has("EMPTY") {
+		t.Error(`Has("EMPTY") = false, want true`)
+	}
+}
+
+func TestWithoutSystemEnv(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("APP_ENV", "")
+	t.Setenv("CFGO_TEST_SYS", "sys")
+	t.Setenv("CFGO_TEST_BOTH", "sys")
+	unsetEnv(t, "CFGO_TEST_FILE")
+	unsetEnv(t, "CFGO_TEST_SET")
+
+	writeFile(t, filepath.Join(dir, ".env"), "CFGO_TEST_FILE=file\nCFGO_TEST_BOTH=file\n")
+
+	conf := NewEnvFile(dir, testLogger{}, WithoutSystemEnv())
+
+	if conf.Has("CFGO_TEST_SYS") {
+		t.Error("a system variable is visible")
+	}
+
+	if got := conf.Get("CFGO_TEST_BOTH"); got != "file" {
+		t.Errorf("Get(CFGO_TEST_BOTH) = %q, want %q", got, "file")
+	}
+
+	if err := conf.Set("CFGO_TEST_SET", "set"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"CFGO_TEST_FILE": "file", "CFGO_TEST_BOTH": "file", "CFGO_TEST_SET": "set"}
+	if got := conf.Snapshot(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Snapshot() = %v, want %v", got, want)
+	}
+
+	// Dropping the key from the file hides the system value again
+	writeFile(t, filepath.Join(dir, ".env"), "CFGO_TEST_FILE=file\n")
+
+	if err := conf.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if conf.Has("CFGO_TEST_BOTH") {
+		t.Error("CFGO_TEST_BOTH is visible after the file dropped it")
+	}
+
+	if got := os.Getenv("CFGO_TEST_BOTH"); got != "sys" {
+		t.Errorf("process CFGO_TEST_BOTH = %q, want %q", got, "sys")
+	}
+}
+
+func TestSetSurvivesReload(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("APP_ENV", "")
+	unsetEnv(t, "CFGO_TEST_PK")
+	unsetEnv(t, "CFGO_TEST_PL")
+	unsetEnv(t, "CFGO_TEST_PE")
+
+	writeFile(t, filepath.Join(dir, ".env"), "CFGO_TEST_PK=file\nCFGO_TEST_PE=file\n")
+	writeFile(t, filepath.Join(dir, ".local.env"), "CFGO_TEST_PL=file\n")
+
+	extra := filepath.Join(t.TempDir(), "extra.env")
+	writeFile(t, extra, "CFGO_TEST_PE=extra\n")
+
+	conf := NewEnvFile(dir, testLogger{})
+
+	if err := conf.SetMany(map[string]string{"CFGO_TEST_PK": "set", "CFGO_TEST_PL": "set"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conf.LoadEnvFile(extra); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conf.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]string{"CFGO_TEST_PK": "set", "CFGO_TEST_PL": "set", "CFGO_TEST_PE": "extra"} {
+		if got := conf.Get(key); got != want {
+			t.Errorf("after reload Get(%s) = %q, want %q", key, got, want)
+		}
+	}
+
+	if err := conf.Unset("CFGO_TEST_PK"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conf.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := conf.Get("CFGO_TEST_PK"); got != "file" {
+		t.Errorf("after Unset and reload Get() = %q, want %q", got, "file")
+	}
+}
+
+func TestKeyReplacerListsDottedKeysOnly(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	t.Setenv("CFGOPQ__HOST", "h")
+
+	conf := NewEnvFile(t.TempDir(), testLogger{}, WithEnvKeyReplacer("__", true))
+
+	if got := conf.KeysWithPrefix("CFGOPQ"); len(got) != 0 {
+		t.Errorf("KeysWithPrefix(CFGOPQ) = %v, want none", got)
+	}
+
+	if got, want := conf.KeysWithPrefix("cfgopq"), []string{"cfgopq.host"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("KeysWithPrefix(cfgopq) = %v, want %v", got, want)
+	}
+
+	snapshot := conf.Snapshot()
+	if _, ok := snapshot["CFGOPQ__HOST"]; ok {
+		t.Error("Snapshot() lists the raw variable name")
+	}
+
+	delete(snapshot, "cfgopq.host")
+
+	if err := conf.Restore(snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	if conf.Has("cfgopq.host") {
+		t.Error("Has() = true after restoring a snapshot without the key")
+	}
+}
+
+func TestSetManyIsAllOrNothing(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	t.Setenv("CFGO_TEST_M1", "old")
+	unsetEnv(t, "CFGO_TEST_M2")
+
+	conf := NewEnvFile(t.TempDir(), testLogger{})
+
+	var changed []string
+	conf.OnChange(func(keys []string) { changed = append(changed, keys...) })
+
+	err := conf.SetMany(map[string]string{"CFGO_TEST_M1": "new", "CFGO_TEST_M2": "new", "BAD=KEY": "x"})
+	if !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("SetMany() error = %v, want ErrInvalidValue", err)
+	}
+
+	if got := conf.Get("CFGO_TEST_M1"); got != "old" {
+		t.Errorf("Get(CFGO_TEST_M1) = %q, want %q", got, "old")
+	}
+
+	if conf.Has("CFGO_TEST_M2") {
+		t.Error("CFGO_TEST_M2 was set")
+	}
+
+	if len(changed) != 0 {
+		t.Errorf("OnChange got %v, want no call", changed)
+	}
+}
+
+func TestWatchDebouncesWrites(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		writes    []string
+		wantCalls int32
+		want      string
+	}{
+		{name: "single write", file: ".env", writes: []string{"CFGO_TEST_W=1\n"}, wantCalls: 1, want: "1"},
+		{name: "burst of writes", file: ".env", writes: []string{"CFGO_TEST_W=1\n", "CFGO_TEST_W=2\n", "CFGO_TEST_W=3\n"}, wantCalls: 1, want: "3"},
+		{name: "other file", file: "other.txt", writes: []string{"CFGO_TEST_W=1\n"}, wantCalls: 0, want: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv("APP_ENV", "")
+			unsetEnv(t, "CFGO_TEST_W")
+
+			writeFile(t, filepath.Join(dir, ".env"), "CFGO_TEST_W=0\n")
+
+			conf := NewEnvFile(dir, testLogger{})
+
+			var calls atomic.Int32
+			w, err := conf.Watch(200*time.Millisecond, func() { calls.Add(1) })
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			t.Cleanup(func() { w.Close() })
+
+			for _, content := range tt.writes {
+				writeFile(t, filepath.Join(dir, tt.file), content)
+				time.Sleep(20 * time.Millisecond)
+			}
+
+			deadline := time.Now().Add(2 * time.Second)
+			for tt.wantCalls > 0 && calls.Load() < tt.wantCalls && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			// Give a second, unwanted reload the chance to happen
+			time.Sleep(400 * time.Millisecond)
+
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("onChange called %d times, want %d", got, tt.wantCalls)
+			}
+
+			if got := conf.Get("CFGO_TEST_W"); got != tt.want {
+				t.Errorf("Get(CFGO_TEST_W) = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
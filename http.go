@@ -0,0 +1,130 @@
+package cfgo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HTTPLoader fetches JSON or YAML config from a URL and merges it into a config with dotted keys.
+// It remembers the ETag and Last-Modified headers of the last response, so polling an unchanged document
+// costs a 304 and no parsing.
+type HTTPLoader struct {
+	config Config
+	url    string
+	client *http.Client
+	header http.Header
+	format string
+
+	mu           sync.Mutex
+	etag         string
+	lastModified string
+}
+
+type HTTPOption func(*HTTPLoader)
+
+// WithHTTPClient replaces the default client, which gives up on a request after 30 seconds. A client
+// without a Timeout waits for as long as the context passed to Load allows.
+func WithHTTPClient(client *http.Client) HTTPOption {
+	return func(l *HTTPLoader) {
+		l.client = client
+	}
+}
+
+// WithHTTPHeader adds a request header, such as Authorization.
+func WithHTTPHeader(key, value string) HTTPOption {
+	return func(l *HTTPLoader) {
+		l.header.Add(key, value)
+	}
+}
+
+// WithHTTPFormat forces the response to be decoded as "json" or "yaml" (or "yml"), in any case, instead of
+// going by its Content-Type. Load returns an error for any other format.
+func WithHTTPFormat(format string) HTTPOption {
+	return func(l *HTTPLoader) {
+		l.format = strings.ToLower(format)
+	}
+}
+
+// defaultHTTPTimeout bounds each request of a loader that was not given a client with WithHTTPClient.
+const defaultHTTPTimeout = 30 * time.Second
+
+// NewHTTPLoader returns a loader that merges the document at url into cfg.
+func NewHTTPLoader(cfg Config, url string, opts ...HTTPOption) *HTTPLoader {
+	l := &HTTPLoader{
+		config: cfg,
+		url:    url,
+		client: &http.Client{Timeout: defaultHTTPTimeout},
+		header: make(http.Header),
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// Load fetches the document and merges it into the config with FromReader, overriding variables that are
+// already set so that repeated loads pick up remote changes, and notifying the OnChange callbacks. Keys
+// removed from the document are left in place. A non-2xx response is returned as an error. Remote
+// servers can stall, so pass a ctx with a deadline, such as one from context.WithTimeout, to bound the load
+// when the client has no Timeout of its own.
+func (l *HTTPLoader) Load(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch l.format {
+	case "", "json", "yaml", "yml":
+	default:
+		return fmt.Errorf("unsupported HTTP config format %q", l.format)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.url, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	req.Header = l.header.Clone()
+
+	if l.etag != "" {
+		req.Header.Set("If-None-Match", l.etag)
+	}
+
+	if l.lastModified != "" {
+		req.Header.Set("If-Modified-Since", l.lastModified)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to load config from %s: %s", l.url, resp.Status)
+	}
+
+	format := l.format
+	if format == "" {
+		format = "json"
+		if strings.Contains(resp.Header.Get("Content-Type"), "yaml") {
+			format = "yaml"
+		}
+	}
+
+	if err = l.config.FromReader(resp.Body, format); err != nil {
+		return fmt.Errorf("failed to load config from %s: %w", l.url, err)
+	}
+
+	l.etag, l.lastModified = resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+
+	return nil
+}
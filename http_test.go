@@ -0,0 +1,76 @@
+package cfgo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestHTTPLoaderNotifiesAndSurvivesReload(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("APP_ENV", "")
+	unsetEnv(t, "db.host")
+
+	writeFile(t, filepath.Join(dir, ".env"), "db.host=file\n")
+	writeFile(t, filepath.Join(dir, ".local.env"), "db.host=local\n")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"db": {"host": "remote"}}`))
+	}))
+	defer srv.Close()
+
+	conf := NewEnvFile(dir, testLogger{})
+
+	var changed []string
+	conf.OnKeyChange("db.host", func(old, new string) { changed = append(changed, new) })
+
+	if err := NewHTTPLoader(conf, srv.URL).Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changed) != 1 || changed[0] != "remote" {
+		t.Errorf("OnKeyChange got %v, want [remote]", changed)
+	}
+
+	if err := conf.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := conf.Get("db.host"); got != "remote" {
+		t.Errorf("after reload Get() = %q, want %q", got, "remote")
+	}
+}
+
+func TestHTTPLoaderFormat(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	unsetEnv(t, "db.host")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("db:\n  host: remote\n"))
+	}))
+	defer srv.Close()
+
+	conf := NewEnvFile(t.TempDir(), testLogger{})
+
+	if err := NewHTTPLoader(conf, srv.URL, WithHTTPFormat("xml")).Load(context.Background()); err == nil {
+		t.Error("Load() with an unsupported format returned no error")
+	}
+
+	if err := NewHTTPLoader(conf, srv.URL, WithHTTPFormat("YML")).Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := conf.Get("db.host"); got != "remote" {
+		t.Errorf("Get() = %q, want %q", got, "remote")
+	}
+}
+
+func TestHTTPLoaderDefaultClientHasTimeout(t *testing.T) {
+	if l := NewHTTPLoader(nil, "http://example.com"); l.client.Timeout <= 0 {
+		t.Errorf("default client Timeout = %v, want a positive timeout", l.client.Timeout)
+	}
+}
@@ -0,0 +1,67 @@
+package cfgo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LoadINI reads the given INI files and merges their values into the config like FromReader. A key in a
+// [section] is stored as section.key, and keys before the first section are top-level. Lines starting with
+// ';' or '#' are comments, and a repeated key keeps its last value.
+func (e *EnvLoader) LoadINI(filenames ...string) error {
+	return e.loadFiles(parseINI, filenames)
+}
+
+func LoadINI(filenames ...string) error {
+	return configInstance.LoadINI(filenames...)
+}
+
+func parseINI(r io.Reader) (map[string]string, error) {
+	var (
+		values  = make(map[string]string)
+		section string
+		scanner = bufio.NewScanner(r)
+	)
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || line[0] == ';' || line[0] == '#':
+			continue
+
+		case line[0] == '[':
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section header %q", lineNo, line)
+			}
+
+			section = strings.TrimSpace(line[1 : len(line)-1])
+
+		default:
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected key=value, got %q", lineNo, line)
+			}
+
+			key = strings.TrimSpace(key)
+			if key == "" {
+				return nil, fmt.Errorf("line %d: missing key", lineNo)
+			}
+
+			values[joinKey(section, key)] = unquote(strings.TrimSpace(value))
+		}
+	}
+
+	return values, scanner.Err()
+}
+
+// unquote strips one pair of matching single or double quotes.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+
+	return s
+}
@@ -0,0 +1,133 @@
+package cfgo
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// LoadJSON reads the given JSON files and merges their values into the config using dotted keys, like
+// FromReader. Arrays of scalars are stored as JSON arrays and arrays of objects as indexed keys such as
+// servers.0.host.
+func (e *EnvLoader) LoadJSON(filenames ...string) error {
+	return e.loadFiles(parseJSON, filenames)
+}
+
+func LoadJSON(filenames ...string) error {
+	return configInstance.LoadJSON(filenames...)
+}
+
+// GetJSON unmarshals the JSON stored under key into target. When key itself is not set but has keys nested
+// under it, as a document loaded with LoadJSON or FromReader leaves them, those are marshalled as an object
+// and unmarshaled into target instead. Their values are typed as WithJSONTypes does, and arrays stored by
+// the loaders are decoded as JSON arrays.
+func (e *EnvLoader) GetJSON(key string, target any) error {
+	if !e.Has(key) {
+		if nested := e.GetStringMapString(key); len(nested) > 0 {
+			b, err := json.Marshal(unflatten(nested, nestedJSONValue))
+			if err == nil {
+				err = json.Unmarshal(b, target)
+			}
+
+			if err != nil {
+				return &ConfigError{Key: key, Err: err}
+			}
+
+			return nil
+		}
+	}
+
+	_, err := parseValue(e, key, func(s string) (any, error) {
+		return nil, json.Unmarshal([]byte(s), target)
+	})
+
+	return err
+}
+
+func nestedJSONValue(s string) any {
+	if strings.HasPrefix(s, "[") && json.Valid([]byte(s)) {
+		return json.RawMessage(s)
+	}
+
+	return typedJSONValue(s)
+}
+
+func GetJSON(key string, target any) error {
+	return configInstance.GetJSON(key, target)
+}
+
+type jsonOptions struct {
+	redact bool
+	typed  bool
+}
+
+type JSONOption func(*jsonOptions)
+
+// WithJSONRedaction replaces the values of secret keys with **** as Dump does.
+func WithJSONRedaction() JSONOption {
+	return func(o *jsonOptions) {
+		o.redact = true
+	}
+}
+
+// WithJSONTypes writes true, false and numeric values as JSON booleans and numbers instead of strings.
+func WithJSONTypes() JSONOption {
+	return func(o *jsonOptions) {
+		o.typed = true
+	}
+}
+
+// WriteJSON writes the config to w as indented JSON, nesting dotted keys so db.host becomes
+// {"db":{"host":...}}. All values are strings unless WithJSONTypes is passed.
+func (e *EnvLoader) WriteJSON(w io.Writer, opts ...JSONOption) error {
+	var o jsonOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	env := e.environ()
+	if o.redact {
+		env = e.Dump()
+	}
+
+	doc := unflatten(env, func(s string) any {
+		if o.typed {
+			return typedJSONValue(s)
+		}
+
+		return s
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(doc)
+}
+
+func typedJSONValue(s string) any {
+	switch {
+	case s == "true":
+		return true
+	case s == "false":
+		return false
+	case s != "" && (s[0] == '-' || s[0] >= '0' && s[0] <= '9') && json.Valid([]byte(s)):
+		return json.Number(s)
+	}
+
+	return s
+}
+
+func parseJSON(r io.Reader) (map[string]string, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	flatten("", doc, values)
+
+	return values, nil
+}
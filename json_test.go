@@ -0,0 +1,49 @@
+package cfgo
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetJSONFallsBackToNestedKeys(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	unsetEnv(t, "flags")
+	unsetEnv(t, "flags.beta")
+	unsetEnv(t, "flags.limit")
+	unsetEnv(t, "flags.tags")
+
+	conf := NewEnvFile(t.TempDir(), testLogger{})
+
+	doc := `{"flags": {"beta": true, "limit": 5, "tags": ["a", "b,c"]}}`
+	if err := conf.FromReader(strings.NewReader(doc), "json"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Beta  bool     `json:"beta"`
+		Limit int      `json:"limit"`
+		Tags  []string `json:"tags"`
+	}
+
+	if err := conf.GetJSON("flags", &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if !got.Beta || got.Limit != 5 || !reflect.DeepEqual(got.Tags, []string{"a", "b,c"}) {
+		t.Errorf("GetJSON() = %+v", got)
+	}
+}
+
+func TestGetJSONMissingKey(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	unsetEnv(t, "CFGO_TEST_MISSING")
+
+	conf := NewEnvFile(t.TempDir(), testLogger{})
+
+	var target map[string]any
+	if err := conf.GetJSON("CFGO_TEST_MISSING", &target); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("GetJSON() error = %v, want ErrKeyNotFound", err)
+	}
+}
@@ -0,0 +1,259 @@
+package cfgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+type parseFunc func(io.Reader) (map[string]string, error)
+
+// formats maps the format names accepted by FromReader to their parsers.
+var formats = map[string]parseFunc{
+	"env":        godotenv.Parse,
+	"json":       parseJSON,
+	"yaml":       parseYAML,
+	"yml":        parseYAML,
+	"toml":       parseTOML,
+	"ini":        parseINI,
+	"properties": parseProperties,
+}
+
+// FromReader parses config in the given format from r and merges it into the config, overriding variables
+// that are already set. Like flags, the values are remembered, so Reload, Watch and StartAutoReload do not
+// replace them with values from the env files. The formats are env, json, yaml (or yml), toml, ini and
+// properties. The keys of the env format are variable names, as in LoadEnvFile, while the keys of the others
+// are config keys, so they get the prefix of WithPrefix. Parse errors carry the line number for yaml, toml,
+// ini and properties, and json reports the byte offset. Errors in the env format, which is parsed by
+// godotenv, name the problem but not where it is.
+func (e *EnvLoader) FromReader(r io.Reader, format string) error {
+	format = strings.ToLower(format)
+
+	parse, ok := formats[format]
+	if !ok {
+		return fmt.Errorf("unsupported config format %q", format)
+	}
+
+	values, err := parse(r)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s config: %w", format, err)
+	}
+
+	if format != "env" {
+		values = e.envNames(values)
+	}
+
+	return e.merge(values)
+}
+
+func FromReader(r io.Reader, format string) error {
+	return configInstance.FromReader(r, format)
+}
+
+// merge exports values, keyed by variable name, overriding variables that are already set, and pins them.
+// The OnChange callbacks receive every key that changed.
+func (e *EnvLoader) merge(values map[string]string) error {
+	e.mu.Lock()
+
+	for name := range values {
+		e.pin(name)
+		e.own(name)
+	}
+
+	changed, err := setEnv(values, true)
+	e.mu.Unlock()
+
+	e.notify(changed)
+
+	return err
+}
+
+// pin marks the variable name as set at runtime, so that reloads neither re-apply nor unset it. It must be
+// called with mu held.
+func (e *EnvLoader) pin(name string) {
+	if e.pinned == nil {
+		e.pinned = make(map[string]bool)
+	}
+
+	e.pinned[name] = true
+
+	// The env files no longer own the key
+	delete(e.loaded, name)
+	delete(e.shadowed, name)
+}
+
+// loadFiles parses each file and merges its values into the config as FromReader does.
+func (e *EnvLoader) loadFiles(parse parseFunc, filenames []string) error {
+	for _, filename := range filenames {
+		values, err := parseFile(parse, filename)
+		if err != nil {
+			return err
+		}
+
+		if err = e.merge(e.envNames(values)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// envNames returns values keyed by the variable names of their keys.
+func (e *EnvLoader) envNames(values map[string]string) map[string]string {
+	names := make(map[string]string, len(values))
+	for key, value := range values {
+		names[e.envName(key)] = value
+	}
+
+	return names
+}
+
+func parseFile(parse parseFunc, filename string) (map[string]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values, err := parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+
+	return values, nil
+}
+
+// setEnv exports values to the environment and returns the sorted names of the variables it changed.
+func setEnv(values map[string]string, override bool) ([]string, error) {
+	var changed []string
+
+	for key, value := range values {
+		old, ok := os.LookupEnv(key)
+		if ok && (!override || old == value) {
+			continue
+		}
+
+		if err := os.Setenv(key, value); err != nil {
+			return changed, err
+		}
+
+		changed = append(changed, key)
+	}
+
+	sort.Strings(changed)
+
+	return changed, nil
+}
+
+// flatten converts a decoded document into dotted keys, so {"db":{"host":"x"}} becomes db.host=x.
+// Arrays of scalars are stored as JSON arrays, such as ["a","b,c"], which GetArray decodes element by
+// element, while arrays holding objects produce indexed keys such as servers.0.host.
+func flatten(prefix string, value any, out map[string]string) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			flatten(joinKey(prefix, key), child, out)
+		}
+	case map[any]any:
+		for key, child := range v {
+			flatten(joinKey(prefix, fmt.Sprint(key)), child, out)
+		}
+	case []map[string]any:
+		for i, child := range v {
+			flatten(joinKey(prefix, strconv.Itoa(i)), child, out)
+		}
+	case []any:
+		if hasObject(v) {
+			for i, child := range v {
+				flatten(joinKey(prefix, strconv.Itoa(i)), child, out)
+			}
+
+			return
+		}
+
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = scalarString(item)
+		}
+
+		b, _ := json.Marshal(items)
+		out[prefix] = string(b)
+	default:
+		out[prefix] = scalarString(v)
+	}
+}
+
+// scalarString formats a decoded scalar the way it is exported, with null as the empty string.
+func scalarString(v any) string {
+	switch v := v.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// unflatten nests dotted keys back into maps, converting each value with convert. When a key is both a
+// value and the parent of other keys, such as db and db.host, the nested keys win.
+func unflatten(values map[string]string, convert func(string) any) map[string]any {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	// Shorter keys first, so parents are visited before the keys nested under them
+	sort.Slice(keys, func(i, j int) bool {
+		return len(keys[i]) < len(keys[j])
+	})
+
+	root := make(map[string]any)
+
+	for _, key := range keys {
+		var (
+			parts = strings.Split(key, ".")
+			node  = root
+		)
+
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				node[part] = child
+			}
+
+			node = child
+		}
+
+		node[parts[len(parts)-1]] = convert(values[key])
+	}
+
+	return root
+}
+
+func hasObject(items []any) bool {
+	for _, item := range items {
+		switch item.(type) {
+		case map[string]any, map[any]any:
+			return true
+		}
+	}
+
+	return false
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
@@ -0,0 +1,175 @@
+package cfgo
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFromReaderKeepsArrayElements(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	unsetEnv(t, "cfgo.tags")
+
+	conf := NewEnvFile(t.TempDir(), testLogger{})
+
+	doc := `{"cfgo": {"tags": ["a,b", " c ", null, 7, "say \"hi\""]}}`
+	if err := conf.FromReader(strings.NewReader(doc), "json"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"a,b", "c", "", "7", `say "hi"`}
+	if got := conf.GetArray("cfgo.tags"); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetArray() = %q, want %q", got, want)
+	}
+}
+
+func TestLoadJSONOverridesWithPrefixAndNotifies(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("APP_ENV", "")
+	t.Setenv("APP_db.host", "old")
+
+	path := filepath.Join(dir, "config.json")
+	writeFile(t, path, `{"db": {"host": "new"}}`)
+
+	conf := NewEnvFile(dir, testLogger{}, WithPrefix("APP_"))
+
+	var changed []string
+	conf.OnChange(func(keys []string) { changed = append(changed, keys...) })
+
+	if err := conf.LoadJSON(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := conf.Get("db.host"); got != "new" {
+		t.Errorf("Get() = %q, want %q", got, "new")
+	}
+
+	if len(changed) != 1 || changed[0] != "db.host" {
+		t.Errorf("OnChange got %v, want [db.host]", changed)
+	}
+}
+
+func TestReloadRestoresFileValueAfterUnset(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("APP_ENV", "")
+	unsetEnv(t, "db.host")
+
+	writeFile(t, filepath.Join(dir, ".env"), "db.host=file\n")
+
+	conf := NewEnvFile(dir, testLogger{})
+
+	if err := conf.FromReader(strings.NewReader("db.host=reader\n"), "env"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conf.Unset("db.host"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conf.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := conf.Get("db.host"); got != "file" {
+		t.Errorf("after reload Get() = %q, want %q", got, "file")
+	}
+}
+
+func TestParseProperties(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want map[string]string
+	}{
+		{"equals", "a=1\nb = 2\n", map[string]string{"a": "1", "b": "2"}},
+		{"colon", "a:1\nb : 2", map[string]string{"a": "1", "b": "2"}},
+		{"whitespace separator", "a 1\nb\t2\nc   =  3", map[string]string{"a": "1", "b": "2", "c": "3"}},
+		{"comments and blank lines", "# c\n! c\n\n  \na=1", map[string]string{"a": "1"}},
+		{"leading whitespace", "   a=1", map[string]string{"a": "1"}},
+		{"key without value", "a\nb=", map[string]string{"a": "", "b": ""}},
+		{"separator in value", "url=http://x:80/a=b", map[string]string{"url": "http://x:80/a=b"}},
+		{"continuation", "a=one, \\\n    two, \\\n    three\nb=2", map[string]string{"a": "one, two, three", "b": "2"}},
+		{"continuation at end of input", "a=one\\", map[string]string{"a": "one"}},
+		{"escaped backslash is no continuation", "a=c:\\\\\nb=2", map[string]string{"a": `c:\`, "b": "2"}},
+		{"comment marker in continuation", "a=x\\\n  # y", map[string]string{"a": "x# y"}},
+		{"escapes", `a=t\tn\nr\rf\fq\"`, map[string]string{"a": "t\tn\nr\rf\fq\""}},
+		{"unicode", `a=caf\u00e9`, map[string]string{"a": "café"}},
+		{"surrogate pair", `a=\ud83d\ude00`, map[string]string{"a": "😀"}},
+		{"escaped separators in key", `a\=b\:c\ d=v`, map[string]string{"a=b:c d": "v"}},
+		{"repeated key keeps the last", "a=1\na=2", map[string]string{"a": "2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseProperties(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseProperties(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePropertiesErrors(t *testing.T) {
+	for _, in := range []string{`a=\u12`, `a=\uzzzz`, "ok=1\n\\u12=x"} {
+		if _, err := parseProperties(strings.NewReader(in)); err == nil {
+			t.Errorf("parseProperties(%q) returned no error", in)
+		}
+	}
+
+	_, err := parseProperties(strings.NewReader("a=1\nb=\\\n  \\u12"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("error = %v, want it to name line 2", err)
+	}
+}
+
+func TestParseINI(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want map[string]string
+	}{
+		{"top-level keys", "a=1\nb = 2 ", map[string]string{"a": "1", "b": "2"}},
+		{"sections", "top=1\n[db]\nhost=x\n[ cache ]\nttl=5", map[string]string{"top": "1", "db.host": "x", "cache.ttl": "5"}},
+		{"nested section name", "[db.primary]\nhost=x", map[string]string{"db.primary.host": "x"}},
+		{"comments and blank lines", "; c\n# c\n\n[s]\n  ; c\nk=v", map[string]string{"s.k": "v"}},
+		{"quoted values", `a="  x  "` + "\nb='y'\nc=\"z'", map[string]string{"a": "  x  ", "b": "y", "c": `"z'`}},
+		{"equals in value", "dsn=a=b", map[string]string{"dsn": "a=b"}},
+		{"empty value", "a=", map[string]string{"a": ""}},
+		{"repeated key keeps the last", "a=1\na=2", map[string]string{"a": "2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseINI(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseINI(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseINIErrors(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"[db\nhost=x", "line 1: unterminated section header"},
+		{"a=1\nnovalue", "line 2: expected key=value"},
+		{"a=1\n\n = x", "line 3: missing key"},
+	}
+
+	for _, tt := range tests {
+		if _, err := parseINI(strings.NewReader(tt.in)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parseINI(%q) error = %v, want %q", tt.in, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package cfgo
+
+import (
+	"fmt"
+	"net"
+)
+
+func (e *EnvLoader) GetIP(key string) (net.IP, error) {
+	return parseValue(e, key, parseIP)
+}
+
+func (e *EnvLoader) GetCIDR(key string) (*net.IPNet, error) {
+	return parseValue(e, key, parseCIDR)
+}
+
+// GetCIDRArray splits the value like GetArray and parses each element as a CIDR such as 10.0.0.0/8,
+// reporting the first element that is not valid. A missing or empty key returns an empty slice.
+func (e *EnvLoader) GetCIDRArray(key string) ([]*net.IPNet, error) {
+	items := e.GetArray(key)
+
+	result := make([]*net.IPNet, 0, len(items))
+	for i, item := range items {
+		n, err := parseCIDR(item)
+		if err != nil {
+			return nil, &ConfigError{Key: key, Err: fmt.Errorf("element %d: %w", i, err)}
+		}
+
+		result = append(result, n)
+	}
+
+	return result, nil
+}
+
+func parseIP(s string) (net.IP, error) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", s)
+	}
+
+	return ip, nil
+}
+
+func parseCIDR(s string) (*net.IPNet, error) {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CIDR %q", s)
+	}
+
+	return n, nil
+}
+
+func GetIP(key string) (net.IP, error) {
+	return configInstance.GetIP(key)
+}
+
+func GetCIDR(key string) (*net.IPNet, error) {
+	return configInstance.GetCIDR(key)
+}
+
+func GetCIDRArray(key string) ([]*net.IPNet, error) {
+	return configInstance.GetCIDRArray(key)
+}
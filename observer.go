@@ -0,0 +1,12 @@
+package cfgo
+
+// Observer receives events for metrics or tracing. Its methods are called without the config lock held,
+// possibly from several goroutines at once, and should return quickly.
+type Observer interface {
+	// OnReload is called after every reload, including those triggered by Watch and StartAutoReload, with
+	// the error it returned.
+	OnReload(err error)
+	// OnGet is called for every Get, and so for every typed getter built on it, reporting whether the key
+	// was set.
+	OnGet(key string, hit bool)
+}
@@ -0,0 +1,178 @@
+package cfgo
+
+import "io/fs"
+
+type Option func(*EnvLoader)
+
+// WithPrefix scopes the config to environment variables starting with prefix, which is stripped from the
+// key: with WithPrefix("MYAPP_"), GetInt("PORT") reads MYAPP_PORT. Keys in the env files are read the
+// same way, so they need the prefix too.
+func WithPrefix(prefix string) Option {
+	return func(e *EnvLoader) {
+		e.prefix = prefix
+	}
+}
+
+// WithCaseInsensitiveKeys makes Get, Set and the other lookups ignore the case of keys. Keys are reported
+// in upper case, and Set writes the upper-case variable. Keys are case-sensitive by default.
+func WithCaseInsensitiveKeys() Option {
+	return func(e *EnvLoader) {
+		e.foldCase = true
+	}
+}
+
+// WithoutDefaultSecrets stops Dump from redacting keys that contain PASSWORD, SECRET, TOKEN or KEY, so only
+// the keys passed to MarkSecret are redacted.
+func WithoutDefaultSecrets() Option {
+	return func(e *EnvLoader) {
+		e.noDefaultSecrets = true
+	}
+}
+
+// WithEnvFiles replaces the default '.env' followed by '.local.env' or '.{APP_ENV}.env' with the given files,
+// loaded in order. Relative names are resolved against the config folder and absolute ones are used as is.
+// Variables set outside the config files take precedence over the first file, and every later file
+// overrides the ones before it.
+func WithEnvFiles(files ...string) Option {
+	return func(e *EnvLoader) {
+		e.envFiles = files
+	}
+}
+
+// WithEnvVarName reads the active environment from the named variable instead of APP_ENV.
+func WithEnvVarName(name string) Option {
+	return func(e *EnvLoader) {
+		e.envVar = name
+	}
+}
+
+// WithDefaultEnv sets the environment used when the environment variable is unset, so that
+// '.{env}.env' is loaded instead of '.local.env'.
+func WithDefaultEnv(env string) Option {
+	return func(e *EnvLoader) {
+		e.defaultEnv = env
+	}
+}
+
+// WithErrorHandler receives the errors from reloads triggered by Watch and StartAutoReload. Without it they
+// are logged as warnings.
+func WithErrorHandler(handler func(error)) Option {
+	return func(e *EnvLoader) {
+		e.errorHandler = handler
+	}
+}
+
+// WithProfile activates a profile within the config: keys under "<profile>." override the base keys with
+// the profile prefix stripped, so with WithProfile("prod") Get("db.host") returns prod.db.host when it is
+// set and db.host otherwise. An INI section [prod] loaded with LoadINI produces such keys. Set and Unset
+// still act on the base key, which a profile key shadows.
+func WithProfile(name string) Option {
+	return func(e *EnvLoader) {
+		e.profile = name
+	}
+}
+
+// WithEnvProfile is like WithProfile with the active environment as the profile, read from APP_ENV or the
+// variable named with WithEnvVarName when the config is created.
+func WithEnvProfile() Option {
+	return func(e *EnvLoader) {
+		e.envProfile = true
+	}
+}
+
+// WithRequiredKeys makes NewEnvFileWithError and every reload return an error listing the given keys that
+// are missing or empty once the env files are loaded, as Require does. The loaded values are applied
+// either way.
+func WithRequiredKeys(keys ...string) Option {
+	return func(e *EnvLoader) {
+		e.required = keys
+	}
+}
+
+// WithEnvKeyReplacer lets variables that cannot contain dots stand for dotted keys: with
+// WithEnvKeyReplacer("__", true), DB__HOST is read as db.host, so Get("db.host") and GetStringMapString("db")
+// find it alongside keys loaded from files. lowercase maps the variable names to lower-case keys; without
+// it DB__HOST is read as DB.HOST. A variable set under the dotted key itself takes precedence.
+func WithEnvKeyReplacer(sep string, lowercase bool) Option {
+	return func(e *EnvLoader) {
+		e.keySep = sep
+		e.keyLower = lowercase
+	}
+}
+
+// SystemEnvPriority decides whether variables already set in the environment win over the env files.
+type SystemEnvPriority int
+
+const (
+	// SystemEnvDefault lets variables already set override the first env file ('.env' by default), while
+	// the env files after it override them.
+	SystemEnvDefault SystemEnvPriority = iota
+	// SystemEnvHigh lets variables already set override every env file.
+	SystemEnvHigh
+	// SystemEnvLow lets every env file override variables already set.
+	SystemEnvLow
+)
+
+// WithSystemEnvPriority sets how variables already present in the environment rank against the env files,
+// SystemEnvDefault unless set. Values applied later with Set, LoadEnvFile and the like are not affected.
+func WithSystemEnvPriority(priority SystemEnvPriority) Option {
+	return func(e *EnvLoader) {
+		e.systemEnvPriority = priority
+	}
+}
+
+// WithoutSystemEnv limits the config to the variables it set itself: the values loaded from the env files
+// and those applied at runtime through it, such as with Set, LoadFlags or FromReader. Every other variable
+// of the process is hidden from lookups, Keys, Dump, Snapshot, WriteJSON, WriteEnvFile and String. Since
+// they are not part of the config, the env files override such variables, as with SystemEnvLow.
+func WithoutSystemEnv() Option {
+	return func(e *EnvLoader) {
+		e.noSystemEnv = true
+	}
+}
+
+// WithDotenvNaming replaces the default '.env' followed by '.local.env' or '.{APP_ENV}.env' with the file
+// names used by dotenv in other ecosystems, loaded in this order so that each overrides the ones before it:
+// '.env', '.env.{APP_ENV}', '.env.local' and '.env.{APP_ENV}.local'. The files naming the environment are
+// skipped when no environment is active. WithEnvFiles takes precedence over this option.
+func WithDotenvNaming() Option {
+	return func(e *EnvLoader) {
+		e.dotenvNaming = true
+	}
+}
+
+// WithObserver sets an Observer to receive reload and lookup events. Without one, no events are produced.
+func WithObserver(observer Observer) Option {
+	return func(e *EnvLoader) {
+		e.observer = observer
+	}
+}
+
+// WithTemplateExpansion renders the values loaded from the env files as text/template templates against
+// the whole config, so URL=https://{{.host}}:{{.port}}/api picks up host and port, and dotted keys are
+// reached as {{.db.host}}. Templates may refer to other templates. A template that fails to parse, names a
+// missing key or takes part in a cycle makes NewEnvFileWithError and Reload return an error. It is opt-in
+// because values may legitimately contain "{{".
+func WithTemplateExpansion() Option {
+	return func(e *EnvLoader) {
+		e.templates = true
+	}
+}
+
+// WithFS reads the env files from fsys, such as an embed.FS, resolving their names against the config
+// folder as usual. A file that fsys does not have is read from disk instead, so defaults baked into the
+// binary can be overridden by files deployed next to it. Absolute file names are always read from disk,
+// and Watch only sees changes on disk.
+func WithFS(fsys fs.FS) Option {
+	return func(e *EnvLoader) {
+		e.fsys = fsys
+	}
+}
+
+// WithDeprecationHandler receives the messages of DeprecateKey, for example to send them to structured
+// logs. Without it they are logged as warnings.
+func WithDeprecationHandler(handler func(key, message string)) Option {
+	return func(e *EnvLoader) {
+		e.deprecationHandler = handler
+	}
+}
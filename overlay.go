@@ -0,0 +1,27 @@
+package cfgo
+
+import "fmt"
+
+// NewOverlay returns a view of base in which override takes precedence: every lookup consults override
+// first, as override itself would, and falls back to base. Has reports a key set in either, and Keys and the
+// other listings return the union with override winning. Every config reads the process environment, so
+// the two hold separate values through their prefixes: with base created with WithPrefix("APP_") and
+// override with WithPrefix("LOCAL_"), Get("db.host") returns LOCAL_db.host when it is set and APP_db.host
+// otherwise. Like GetSub, the view reads through to the environment and shares the change callbacks and
+// reload state of base, and Set and Unset act on base. Both configs must be created by this package.
+func NewOverlay(base, override Config) (Config, error) {
+	b, ok := base.(*EnvLoader)
+	if !ok {
+		return nil, fmt.Errorf("cannot overlay a config of type %T", base)
+	}
+
+	o, ok := override.(*EnvLoader)
+	if !ok {
+		return nil, fmt.Errorf("cannot overlay a config of type %T", override)
+	}
+
+	view := *b
+	view.overlays = append([]*EnvLoader{o}, b.overlays...)
+
+	return &view, nil
+}
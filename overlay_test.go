@@ -0,0 +1,88 @@
+package cfgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewOverlay(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	t.Setenv("BASE_db.host", "base")
+	t.Setenv("BASE_db.port", "5432")
+	t.Setenv("LOCAL_db.host", "local")
+	t.Setenv("LOCAL_debug", "true")
+
+	dir := t.TempDir()
+	base := NewEnvFile(dir, testLogger{}, WithPrefix("BASE_"))
+	override := NewEnvFile(dir, testLogger{}, WithPrefix("LOCAL_"))
+
+	conf, err := NewOverlay(base, override)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := conf.Get("db.host"); got != "local" {
+		t.Errorf("Get(db.host) = %q, want %q", got, "local")
+	}
+
+	if got := conf.Get("db.port"); got != "5432" {
+		t.Errorf("Get(db.port) = %q, want %q", got, "5432")
+	}
+
+	if !conf.Has("debug") {
+		t.Error("Has(debug) = false, want true")
+	}
+
+	want := map[string]string{"host": "local", "port": "5432"}
+	if got := conf.GetStringMapString("db"); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetStringMapString(db) = %v, want %v", got, want)
+	}
+
+	// The configs themselves are unchanged
+	if got := base.Get("db.host"); got != "base" {
+		t.Errorf("base Get(db.host) = %q, want %q", got, "base")
+	}
+}
+
+func TestNewOverlayHonoursOverrideOptions(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	t.Setenv("BASE_k", "base")
+	t.Setenv("LOCAL_DB__HOST", "replaced")
+	t.Setenv("LOCAL_NAME", "folded")
+	t.Setenv("LOCAL_new", "aliased")
+
+	dir := t.TempDir()
+	base := NewEnvFile(dir, testLogger{}, WithPrefix("BASE_"))
+	override := NewEnvFile(dir, testLogger{}, WithPrefix("LOCAL_"), WithEnvKeyReplacer("__", true))
+
+	base.SetDefault("d", "basedef")
+	override.SetDefault("d", "overdef")
+	override.Alias("old", "new")
+
+	conf, err := NewOverlay(base, override)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]string{
+		"d":       "overdef",
+		"k":       "base",
+		"db.host": "replaced",
+		"old":     "aliased",
+	} {
+		if got := conf.Get(key); got != want {
+			t.Errorf("Get(%s) = %q, want %q", key, got, want)
+		}
+	}
+
+	folded := NewEnvFile(dir, testLogger{}, WithPrefix("LOCAL_"), WithCaseInsensitiveKeys())
+
+	conf, err = NewOverlay(base, folded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := conf.Get("name"); got != "folded" {
+		t.Errorf("Get(name) = %q, want %q", got, "folded")
+	}
+}
@@ -0,0 +1,65 @@
+package cfgo
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = map[string]func(string) (any, error){}
+)
+
+func init() {
+	RegisterParser("string", func(s string) (any, error) { return s, nil })
+	RegisterParser("int", anyParser(strconv.Atoi))
+	RegisterParser("int64", anyParser(parseInt64))
+	RegisterParser("float64", anyParser(parseFloat64))
+	RegisterParser("bool", anyParser(parseBool))
+	RegisterParser("duration", anyParser(time.ParseDuration))
+	RegisterParser("bytes", anyParser(parseByteSize))
+	RegisterParser("url", anyParser(url.Parse))
+	RegisterParser("base64", anyParser(parseBase64))
+	RegisterParser("hex", anyParser(hex.DecodeString))
+	RegisterParser("ip", anyParser(parseIP))
+	RegisterParser("cidr", anyParser(parseCIDR))
+	RegisterParser("array", func(s string) (any, error) { return splitArray(s, defaultSeparator), nil })
+}
+
+// RegisterParser makes parse available to GetParsed under name, replacing any parser already registered
+// under it. The built-in parsers are string, int, int64, float64, bool, duration, bytes, url,
+// base64, hex, ip, cidr and array.
+func RegisterParser(name string, parse func(string) (any, error)) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+
+	parsers[name] = parse
+}
+
+// GetParsed converts the value of key with the parser registered under parserName. A missing or empty
+// key is an error, as is an unknown parser.
+func (e *EnvLoader) GetParsed(key, parserName string) (any, error) {
+	parsersMu.RLock()
+	parse, ok := parsers[parserName]
+	parsersMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("no parser registered as %q for config key %q", parserName, key)
+	}
+
+	return parseValue(e, key, parse)
+}
+
+func anyParser[T any](parse func(string) (T, error)) func(string) (any, error) {
+	return func(s string) (any, error) {
+		return parse(s)
+	}
+}
+
+func GetParsed(key, parserName string) (any, error) {
+	return configInstance.GetParsed(key, parserName)
+}
@@ -0,0 +1,172 @@
+package cfgo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// LoadProperties reads the given Java .properties files and merges their values into the config like
+// FromReader. It follows the java.util.Properties rules: '=' or ':' or whitespace separates key and value,
+// lines starting with '#' or '!' are comments, a trailing backslash continues the line, and \t, \n, \r, \f
+// and \uXXXX escapes are decoded.
+func (e *EnvLoader) LoadProperties(filenames ...string) error {
+	return e.loadFiles(parseProperties, filenames)
+}
+
+func LoadProperties(filenames ...string) error {
+	return configInstance.LoadProperties(filenames...)
+}
+
+func parseProperties(r io.Reader) (map[string]string, error) {
+	var (
+		values     = make(map[string]string)
+		logical    strings.Builder
+		continuing bool
+		start      int
+		scanner    = bufio.NewScanner(r)
+	)
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+
+		if !continuing {
+			if line == "" || line[0] == '#' || line[0] == '!' {
+				continue
+			}
+
+			start = lineNo
+		}
+
+		if trailingBackslashes(line)%2 == 1 {
+			logical.WriteString(line[:len(line)-1])
+			continuing = true
+
+			continue
+		}
+
+		logical.WriteString(line)
+		continuing = false
+
+		if err := addProperty(values, logical.String()); err != nil {
+			return nil, fmt.Errorf("line %d: %w", start, err)
+		}
+
+		logical.Reset()
+	}
+
+	if continuing {
+		if err := addProperty(values, logical.String()); err != nil {
+			return nil, fmt.Errorf("line %d: %w", start, err)
+		}
+	}
+
+	return values, scanner.Err()
+}
+
+func trailingBackslashes(s string) int {
+	n := 0
+	for i := len(s) - 1; i >= 0 && s[i] == '\\'; i-- {
+		n++
+	}
+
+	return n
+}
+
+func addProperty(values map[string]string, line string) error {
+	i := 0
+	for i < len(line) && !strings.ContainsRune("=: \t\f", rune(line[i])) {
+		if line[i] == '\\' {
+			i++
+		}
+
+		i++
+	}
+
+	i = min(i, len(line))
+
+	rest := strings.TrimLeft(line[i:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+
+	key, err := unescapeProperty(line[:i])
+	if err != nil {
+		return err
+	}
+
+	value, err := unescapeProperty(rest)
+	if err != nil {
+		return err
+	}
+
+	values[key] = value
+
+	return nil
+}
+
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var (
+		b     strings.Builder
+		units []uint16
+	)
+
+	flush := func() {
+		b.WriteString(string(utf16.Decode(units)))
+		units = units[:0]
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			flush()
+			b.WriteByte(s[i])
+
+			continue
+		}
+
+		i++
+
+		if s[i] == 'u' {
+			if i+5 > len(s) {
+				return "", fmt.Errorf("malformed \\u escape in %q", s)
+			}
+
+			n, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("malformed \\u escape in %q", s)
+			}
+
+			// Collect UTF-16 code units so that surrogate pairs decode to a single rune
+			units = append(units, uint16(n))
+			i += 4
+
+			continue
+		}
+
+		flush()
+
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	flush()
+
+	return b.String(), nil
+}
@@ -0,0 +1,91 @@
+package cfgo
+
+import (
+	"sort"
+	"strings"
+)
+
+const redacted = "****"
+
+// defaultSecretMarkers are the substrings that make a key secret unless WithoutDefaultSecrets is used.
+var defaultSecretMarkers = []string{"PASSWORD", "SECRET", "TOKEN", "KEY"}
+
+// MarkSecret marks keys as sensitive so Dump redacts their values.
+func (e *EnvLoader) MarkSecret(keys ...string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.secrets == nil {
+		e.secrets = make(map[string]bool)
+	}
+
+	for _, key := range keys {
+		e.secrets[e.envName(key)] = true
+	}
+}
+
+// Dump returns every key visible through the config, with the values of secret keys replaced by ****.
+func (e *EnvLoader) Dump() map[string]string {
+	env := e.environ()
+	for key := range env {
+		if e.isSecret(key) {
+			env[key] = redacted
+		}
+	}
+
+	return env
+}
+
+func (e *EnvLoader) isSecret(key string) bool {
+	e.mu.Lock()
+	marked := e.secrets[e.envName(key)]
+	e.mu.Unlock()
+
+	if marked || e.noDefaultSecrets {
+		return marked
+	}
+
+	// The markers apply to the key alone, so that a prefix such as MONKEY_ does not make every key secret
+	upper := strings.ToUpper(key)
+	for _, marker := range defaultSecretMarkers {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String renders the keys visible through the config and their values as Dump returns them, so secret
+// values are redacted when the config ends up in a log line.
+func (e *EnvLoader) String() string {
+	env := e.Dump()
+
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var b strings.Builder
+
+	b.WriteByte('{')
+
+	for i, key := range keys {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+
+		b.WriteString(key + "=" + env[key])
+	}
+
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+// GoString makes %#v redact secrets as String does, instead of printing the struct.
+func (e *EnvLoader) GoString() string {
+	return "cfgo.EnvLoader" + e.String()
+}
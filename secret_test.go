@@ -0,0 +1,21 @@
+package cfgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDumpIgnoresSecretMarkersInPrefix(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	t.Setenv("MONKEY_PORT", "8080")
+	t.Setenv("MONKEY_API_TOKEN", "t")
+	t.Setenv("MONKEY_NAME", "n")
+
+	conf := NewEnvFile(t.TempDir(), testLogger{}, WithPrefix("MONKEY_"))
+	conf.MarkSecret("NAME")
+
+	want := map[string]string{"PORT": "8080", "API_TOKEN": "****", "NAME": "****"}
+	if got := conf.Dump(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Dump() = %v, want %v", got, want)
+	}
+}
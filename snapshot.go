@@ -0,0 +1,96 @@
+package cfgo
+
+import (
+	"os"
+	"sort"
+)
+
+// Snapshot returns a copy of every key visible through the config and its value.
+func (e *EnvLoader) Snapshot() map[string]string {
+	return e.environ()
+}
+
+// Restore makes the visible keys match snapshot: keys missing from it are unset, along with their default
+// if they have one, and the others are set to their snapshot value. The OnChange callbacks receive every
+// key that changed.
+func (e *EnvLoader) Restore(snapshot map[string]string) error {
+	e.mu.Lock()
+
+	var (
+		current = e.environ()
+		changed []string
+	)
+
+	for key := range current {
+		if _, ok := snapshot[key]; ok {
+			continue
+		}
+
+		names, err := e.unset(key)
+		if err != nil {
+			e.mu.Unlock()
+			return err
+		}
+
+		e.keysMu.Lock()
+		delete(e.defaults, e.envName(key))
+		e.keysMu.Unlock()
+
+		if len(names) == 0 {
+			// Only the default was visible
+			names = []string{e.envName(key)}
+		}
+
+		changed = append(changed, names...)
+	}
+
+	for key, value := range snapshot {
+		if old, ok := current[key]; ok && old == value {
+			continue
+		}
+
+		if err := os.Setenv(e.envName(key), value); err != nil {
+			e.mu.Unlock()
+			return err
+		}
+
+		e.own(e.envName(key))
+
+		changed = append(changed, e.envName(key))
+	}
+
+	e.mu.Unlock()
+
+	sort.Strings(changed)
+	e.notify(changed)
+
+	return nil
+}
+
+// Diff compares the keys visible through the config with those visible through other and returns each key
+// whose value differs, mapped to its value in this config and in other. The value is nil on the side that
+// does not have the key. Configs created by this package share the process environment, so Diff is
+// mostly useful between views with different prefixes or profiles, or against a config of another type.
+func (e *EnvLoader) Diff(other Config) map[string][2]any {
+	var (
+		mine   = e.Snapshot()
+		theirs = other.Snapshot()
+		diff   = make(map[string][2]any)
+	)
+
+	for k, v := range mine {
+		if ov, ok := theirs[k]; !ok {
+			diff[k] = [2]any{v, nil}
+		} else if ov != v {
+			diff[k] = [2]any{v, ov}
+		}
+	}
+
+	for k, ov := range theirs {
+		if _, ok := mine[k]; !ok {
+			diff[k] = [2]any{nil, ov}
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,28 @@
+package cfgo
+
+import "testing"
+
+func TestRestoreRemovesDefaults(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	unsetEnv(t, "CFGO_TEST_D")
+
+	conf := NewEnvFile(t.TempDir(), testLogger{})
+	snapshot := conf.Snapshot()
+
+	conf.SetDefault("CFGO_TEST_D", "default")
+
+	var changed []string
+	conf.OnChange(func(keys []string) { changed = append(changed, keys...) })
+
+	if err := conf.Restore(snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	if conf.Has("CFGO_TEST_D") {
+		t.Error("Has() = true after Restore, want false")
+	}
+
+	if len(changed) != 1 || changed[0] != "CFGO_TEST_D" {
+		t.Errorf("OnChange got %v, want [CFGO_TEST_D]", changed)
+	}
+}
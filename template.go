@@ -0,0 +1,207 @@
+package cfgo
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// maxTemplatePasses bounds template expansion. Cycles are rejected before rendering, so only a chain of
+// templates longer than this fails to settle.
+const maxTemplatePasses = 10
+
+// expandTemplates renders the values that contain "{{" as text/template templates against the config with
+// values applied, repeating until no value changes so that templates may refer to other templates.
+func (e *EnvLoader) expandTemplates(values map[string]string) error {
+	names := make([]string, 0, len(values))
+	for name, v := range values {
+		if strings.Contains(v, "{{") {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	if err := e.checkTemplateCycles(values, names); err != nil {
+		return err
+	}
+
+	for pass := 0; pass < maxTemplatePasses; pass++ {
+		var (
+			data    = e.templateData(values)
+			changed bool
+			errs    []error
+		)
+
+		for _, name := range names {
+			out, err := renderTemplate(name, values[name], data)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to expand config %v: %w", name, err))
+				continue
+			}
+
+			if out != values[name] {
+				values[name] = out
+				changed = true
+			}
+		}
+
+		if !changed || len(errs) > 0 {
+			return errors.Join(errs...)
+		}
+	}
+
+	return fmt.Errorf("config templates did not settle after %d passes, they may refer to each other", maxTemplatePasses)
+}
+
+// checkTemplateCycles returns an error naming the keys of the first cycle among the templates of names.
+// A template depends on the keys its fields refer to, such as db.host for {{.db.host}} or every key under
+// db for {{.db}}. Rendering alone cannot tell a cycle apart from a settled value, since PA={{.PA}} renders
+// to itself.
+func (e *EnvLoader) checkTemplateCycles(values map[string]string, names []string) error {
+	keys := make(map[string]string, len(names))
+	for _, name := range names {
+		if k, ok := e.trimPrefix(name, e.prefix); ok {
+			keys[name] = e.canonical(k)
+		}
+	}
+
+	deps := make(map[string][]string, len(names))
+	for _, name := range names {
+		tmpl, err := template.New(name).Parse(values[name])
+		if err != nil {
+			// Reported by the render
+			continue
+		}
+
+		for _, field := range templateFields(tmpl.Root) {
+			for _, other := range names {
+				if k, ok := keys[other]; ok && (k == field || strings.HasPrefix(k, field+".")) {
+					deps[name] = append(deps[name], other)
+				}
+			}
+		}
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+
+	var (
+		state = make(map[string]int, len(names))
+		path  []string
+		visit func(name string) error
+	)
+
+	visit = func(name string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			i := slices.Index(path, name)
+			cycle := append(slices.Clone(path[i:]), name)
+
+			return fmt.Errorf("config templates refer to each other in a cycle: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+
+		for _, dep := range deps[name] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[name] = done
+
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// templateFields returns the dotted keys that the fields of a template refer to, such as db.host for
+// {{.db.host}}. Fields inside range and with blocks are relative to another value and are skipped.
+func templateFields(node parse.Node) []string {
+	var fields []string
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+
+		for _, child := range n.Nodes {
+			fields = append(fields, templateFields(child)...)
+		}
+	case *parse.ActionNode:
+		fields = templateFields(n.Pipe)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+
+		for _, cmd := range n.Cmds {
+			fields = append(fields, templateFields(cmd)...)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			fields = append(fields, templateFields(arg)...)
+		}
+	case *parse.FieldNode:
+		fields = append(fields, strings.Join(n.Ident, "."))
+	case *parse.IfNode:
+		fields = append(templateFields(n.Pipe), templateFields(n.List)...)
+		fields = append(fields, templateFields(n.ElseList)...)
+	case *parse.RangeNode:
+		fields = append(templateFields(n.Pipe), templateFields(n.ElseList)...)
+	case *parse.WithNode:
+		fields = append(templateFields(n.Pipe), templateFields(n.ElseList)...)
+	case *parse.TemplateNode:
+		fields = templateFields(n.Pipe)
+	}
+
+	return fields
+}
+
+// templateData returns the keys visible through the config, with values applied, nested at their dots so
+// that {{.db.host}} reads db.host.
+func (e *EnvLoader) templateData(values map[string]string) map[string]any {
+	env := e.environ()
+	for name, v := range values {
+		if k, ok := e.trimPrefix(name, e.prefix); ok {
+			env[e.canonical(k)] = v
+		}
+	}
+
+	return unflatten(env, func(s string) any {
+		return s
+	})
+}
+
+func renderTemplate(name, text string, data map[string]any) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,70 @@
+package cfgo
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemplateExpansion(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("APP_ENV", "")
+
+	for _, key := range []string{"host", "port", "URL", "API", "db.host", "DSN"} {
+		unsetEnv(t, key)
+	}
+
+	writeFile(t, filepath.Join(dir, ".env"), strings.Join([]string{
+		"host=example.com",
+		"port=8080",
+		"URL=https://{{.host}}:{{.port}}",
+		"API={{.URL}}/api",
+		"db.host=db.local",
+		"DSN=postgres://{{.db.host}}/app",
+	}, "\n"))
+
+	conf, err := NewEnvFileWithError(dir, testLogger{}, WithTemplateExpansion())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]string{
+		"URL": "https://example.com:8080",
+		"API": "https://example.com:8080/api",
+		"DSN": "postgres://db.local/app",
+	} {
+		if got := conf.Get(key); got != want {
+			t.Errorf("Get(%s) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestTemplateExpansionRejectsCycles(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		cycle   string
+	}{
+		{"self reference", "PS={{.PS}}\n", "PS -> PS"},
+		{"two keys", "PA={{.PB}}\nPB={{.PA}}\n", "PA -> PB -> PA"},
+		{"through a parent", "db.host={{.db}}\n", "db.host -> db.host"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv("APP_ENV", "")
+
+			for _, key := range []string{"PS", "PA", "PB", "db.host"} {
+				unsetEnv(t, key)
+			}
+
+			writeFile(t, filepath.Join(dir, ".env"), tt.content)
+
+			_, err := NewEnvFileWithError(dir, testLogger{}, WithTemplateExpansion())
+			if err == nil || !strings.Contains(err.Error(), tt.cycle) {
+				t.Errorf("NewEnvFileWithError() error = %v, want a cycle %s", err, tt.cycle)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package cfgo
+
+import (
+	"io"
+
+	"github.com/BurntSushi/toml"
+)
+
+// LoadTOML reads the given TOML files and merges their values into the config using dotted keys, like
+// FromReader. Arrays of tables produce indexed keys such as servers.0.host.
+func (e *EnvLoader) LoadTOML(filenames ...string) error {
+	return e.loadFiles(parseTOML, filenames)
+}
+
+func LoadTOML(filenames ...string) error {
+	return configInstance.LoadTOML(filenames...)
+}
+
+func parseTOML(r io.Reader) (map[string]string, error) {
+	var doc map[string]any
+	if _, err := toml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	flatten("", doc, values)
+
+	return values, nil
+}
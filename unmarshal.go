@@ -0,0 +1,266 @@
+package cfgo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const tagName = "cfgo"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+type fieldTag struct {
+	key        string
+	omitEmpty  bool
+	required   bool
+	hasDefault bool
+	defaultVal string
+	min, max   string
+}
+
+// parseFieldTag parses `cfgo:"KEY,opt,..."`. The options are omitempty, required, default=<value>,
+// min=<bound> and max=<bound>; since options are comma separated, a default cannot contain a comma.
+func parseFieldTag(tag string) fieldTag {
+	parts := strings.Split(tag, ",")
+	ft := fieldTag{key: strings.TrimSpace(parts[0])}
+	for _, opt := range parts[1:] {
+		name, value, _ := strings.Cut(strings.TrimSpace(opt), "=")
+		switch name {
+		case "omitempty":
+			ft.omitEmpty = true
+		case "required":
+			ft.required = true
+		case "default":
+			ft.hasDefault, ft.defaultVal = true, value
+		case "min":
+			ft.min = value
+		case "max":
+			ft.max = value
+		}
+	}
+	return ft
+}
+
+// Unmarshal populates the struct pointed to by target from the config. Fields are mapped through their
+// `cfgo:"KEY"` tag, and a struct field tagged `cfgo:"db"` reads its own fields from "db.<key>". A missing
+// or empty key takes the value of the default option if there is one, is skipped with omitempty and is
+// an error otherwise, even with omitempty when the field is marked required. Numeric and duration fields
+// are checked against the min and max options. Every failing field is reported in the returned error.
+func (e *EnvLoader) Unmarshal(target any) error {
+	if err := checkTarget(target); err != nil {
+		return err
+	}
+
+	return errors.Join(e.unmarshalStruct(reflect.ValueOf(target).Elem(), "")...)
+}
+
+// WatchUnmarshal unmarshals the config into target like Unmarshal, then again whenever a value changes.
+// target must be a non-nil pointer to a struct. Each update is unmarshaled into a fresh struct and copied
+// into target only if it succeeds, with lock held during the copy when it is not nil, so readers that take
+// the same lock never see a half-updated struct. callback, if not nil, runs after every update with its
+// error. It returns the error of the first unmarshal, in which case nothing is watched, and otherwise a
+// function that stops the updates and releases the callback registered for them.
+func (e *EnvLoader) WatchUnmarshal(target any, lock sync.Locker, callback func(err error)) (func(), error) {
+	if err := checkTarget(target); err != nil {
+		return nil, err
+	}
+
+	update := func() error {
+		fresh := reflect.New(reflect.TypeOf(target).Elem())
+		if err := e.Unmarshal(fresh.Interface()); err != nil {
+			return err
+		}
+
+		if lock != nil {
+			lock.Lock()
+			defer lock.Unlock()
+		}
+
+		reflect.ValueOf(target).Elem().Set(fresh.Elem())
+
+		return nil
+	}
+
+	if err := update(); err != nil {
+		return nil, err
+	}
+
+	unsubscribe := e.Subscribe(func([]string) {
+		err := update()
+		if callback != nil {
+			callback(err)
+		}
+	})
+
+	return unsubscribe, nil
+}
+
+func checkTarget(target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("unmarshal target must be a non-nil pointer to a struct, got %T", target)
+	}
+
+	return nil
+}
+
+func (e *EnvLoader) unmarshalStruct(v reflect.Value, prefix string) []error {
+	var (
+		t    = v.Type()
+		errs []error
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag, ok := field.Tag.Lookup(tagName)
+		if !ok || tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		var (
+			ft  = parseFieldTag(tag)
+			key = prefix + ft.key
+			fv  = v.Field(i)
+		)
+
+		if fv.Kind() == reflect.Struct {
+			errs = append(errs, e.unmarshalStruct(fv, key+".")...)
+			continue
+		}
+
+		raw := e.Get(key)
+		if raw == "" {
+			switch {
+			case ft.hasDefault:
+				raw = ft.defaultVal
+			case ft.omitEmpty && !ft.required:
+				continue
+			default:
+				errs = append(errs, fmt.Errorf("missing config key %q for field %s", key, field.Name))
+				continue
+			}
+		}
+
+		if err := setField(fv, raw); err != nil {
+			errs = append(errs, fmt.Errorf("invalid value for config key %q (field %s): %w", key, field.Name, err))
+			continue
+		}
+
+		if err := checkBounds(fv, ft.min, ft.max); err != nil {
+			errs = append(errs, fmt.Errorf("invalid value for config key %q (field %s): %w", key, field.Name, err))
+		}
+	}
+
+	return errs
+}
+
+func checkBounds(v reflect.Value, min, max string) error {
+	if min == "" && max == "" {
+		return nil
+	}
+
+	var (
+		val   float64
+		parse = parseFloat64
+	)
+
+	switch {
+	case v.Type() == durationType:
+		val = float64(v.Int())
+		parse = func(s string) (float64, error) {
+			d, err := time.ParseDuration(s)
+			return float64(d), err
+		}
+	case v.CanInt():
+		val = float64(v.Int())
+	case v.CanFloat():
+		val = v.Float()
+	default:
+		return fmt.Errorf("min and max are not supported for type %s", v.Type())
+	}
+
+	if min != "" {
+		bound, err := parse(min)
+		if err != nil {
+			return fmt.Errorf("invalid min %q: %w", min, err)
+		}
+
+		if val < bound {
+			return fmt.Errorf("%v is less than min %s", v.Interface(), min)
+		}
+	}
+
+	if max != "" {
+		bound, err := parse(max)
+		if err != nil {
+			return fmt.Errorf("invalid max %q: %w", max, err)
+		}
+
+		if val > bound {
+			return fmt.Errorf("%v is greater than max %s", v.Interface(), max)
+		}
+	}
+
+	return nil
+}
+
+func setField(v reflect.Value, raw string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := parseBool(raw)
+		if err != nil {
+			return err
+		}
+
+		v.SetBool(b)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported slice type %s", v.Type())
+		}
+
+		items := splitArray(raw, defaultSeparator)
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			slice.Index(i).SetString(item)
+		}
+
+		v.Set(slice)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+
+	return nil
+}
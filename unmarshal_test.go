@@ -0,0 +1,128 @@
+package cfgo
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchUnmarshalStopRemovesCallback(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	t.Setenv("CFGO_TEST_PORT", "1")
+
+	conf := NewEnvFile(t.TempDir(), testLogger{}).(*EnvLoader)
+
+	var target struct {
+		Port int `cfgo:"CFGO_TEST_PORT"`
+	}
+
+	before := len(conf.callbacks)
+
+	stop, err := conf.WatchUnmarshal(&target, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stop()
+
+	if got := len(conf.callbacks); got != before {
+		t.Errorf("%d callbacks after stop, want %d", got, before)
+	}
+
+	if err := conf.Set("CFGO_TEST_PORT", "2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if target.Port != 1 {
+		t.Errorf("Port = %d after stop, want 1", target.Port)
+	}
+}
+
+func TestUnmarshalTags(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+
+	type target struct {
+		Port    int           `cfgo:"CFGO_TEST_TAG_PORT,default=8080,min=1,max=65535"`
+		Name    string        `cfgo:"CFGO_TEST_TAG_NAME,required"`
+		Label   string        `cfgo:"CFGO_TEST_TAG_LABEL,omitempty"`
+		Timeout time.Duration `cfgo:"CFGO_TEST_TAG_TIMEOUT,omitempty,min=1s,max=1m"`
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    target
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			env:  map[string]string{"CFGO_TEST_TAG_NAME": "app"},
+			want: target{Port: 8080, Name: "app"},
+		},
+		{
+			name: "values",
+			env: map[string]string{
+				"CFGO_TEST_TAG_PORT":    "9000",
+				"CFGO_TEST_TAG_NAME":    "app",
+				"CFGO_TEST_TAG_LABEL":   "blue",
+				"CFGO_TEST_TAG_TIMEOUT": "30s",
+			},
+			want: target{Port: 9000, Name: "app", Label: "blue", Timeout: 30 * time.Second},
+		},
+		{
+			name:    "missing required",
+			env:     map[string]string{},
+			wantErr: `missing config key "CFGO_TEST_TAG_NAME"`,
+		},
+		{
+			name:    "below min",
+			env:     map[string]string{"CFGO_TEST_TAG_NAME": "app", "CFGO_TEST_TAG_PORT": "0"},
+			wantErr: "0 is less than min 1",
+		},
+		{
+			name:    "above max",
+			env:     map[string]string{"CFGO_TEST_TAG_NAME": "app", "CFGO_TEST_TAG_PORT": "70000"},
+			wantErr: "70000 is greater than max 65535",
+		},
+		{
+			name:    "duration above max",
+			env:     map[string]string{"CFGO_TEST_TAG_NAME": "app", "CFGO_TEST_TAG_TIMEOUT": "2m"},
+			wantErr: "2m0s is greater than max 1m",
+		},
+		{
+			name:    "invalid value",
+			env:     map[string]string{"CFGO_TEST_TAG_NAME": "app", "CFGO_TEST_TAG_PORT": "http"},
+			wantErr: `invalid value for config key "CFGO_TEST_TAG_PORT"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"CFGO_TEST_TAG_PORT", "CFGO_TEST_TAG_NAME", "CFGO_TEST_TAG_LABEL", "CFGO_TEST_TAG_TIMEOUT"} {
+				unsetEnv(t, key)
+			}
+
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			var got target
+			err := NewEnvFile(t.TempDir(), testLogger{}).Unmarshal(&got)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Unmarshal() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Unmarshal() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package cfgo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Validate runs each rule against the value of its key and returns every failure joined into one error.
+// The value is passed as a string, or as nil when the key is not set.
+func (e *EnvLoader) Validate(rules map[string]func(any) error) error {
+	keys := make([]string, 0, len(rules))
+	for key := range rules {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var errs []error
+
+	for _, key := range keys {
+		var value any
+		if val, ok := e.lookup(key); ok {
+			value = val
+		}
+
+		if err := rules[key](value); err != nil {
+			errs = append(errs, fmt.Errorf("config key %q: %w", key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Require returns an error listing every key that is missing or empty.
+func (e *EnvLoader) Require(keys ...string) error {
+	var missing []string
+
+	for _, key := range keys {
+		if e.Get(key) == "" {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required config keys: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+func Validate(rules map[string]func(any) error) error {
+	return configInstance.Validate(rules)
+}
+
+func Require(keys ...string) error {
+	return configInstance.Require(keys...)
+}
@@ -0,0 +1,129 @@
+package cfgo
+
+import (
+	"io"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+type fileWatcher struct {
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// Watch reloads the config whenever one of the env files changes, and calls onChange after each successful
+// reload. Writes that arrive within debounce of each other trigger a single reload. Closing the returned
+// io.Closer stops the watcher and waits for it to exit.
+func (e *EnvLoader) Watch(debounce time.Duration, onChange func()) (io.Closer, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	// Watch the folders rather than the files, since editors often replace a file instead of writing it
+	dirs := make(map[string]bool)
+	for _, file := range e.files() {
+		dirs[filepath.Dir(file.path)] = true
+	}
+
+	for dir := range dirs {
+		if err = watcher.Add(dir); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+	}
+
+	w := &fileWatcher{
+		watcher: watcher,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go w.run(e, debounce, onChange)
+
+	return w, nil
+}
+
+func (w *fileWatcher) run(e *EnvLoader, debounce time.Duration, onChange func()) {
+	defer close(w.stopped)
+
+	var (
+		timer *time.Timer
+		fire  <-chan time.Time
+	)
+
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-w.done:
+			return
+
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+
+			if event.Op == fsnotify.Chmod || !e.isEnvFile(event.Name) {
+				continue
+			}
+
+			if timer != nil {
+				timer.Stop()
+			}
+
+			timer = time.NewTimer(debounce)
+			fire = timer.C
+
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+
+			e.logger.Warnf("Failed to watch config files, Err: %v", err)
+
+		case <-fire:
+			fire = nil
+
+			if err := e.Reload(); err != nil {
+				e.handleError(err)
+				continue
+			}
+
+			if onChange != nil {
+				onChange()
+			}
+		}
+	}
+}
+
+func (w *fileWatcher) Close() error {
+	var err error
+
+	w.once.Do(func() {
+		close(w.done)
+		<-w.stopped
+		err = w.watcher.Close()
+	})
+
+	return err
+}
+
+func (e *EnvLoader) isEnvFile(name string) bool {
+	for _, file := range e.files() {
+		if filepath.Clean(file.path) == filepath.Clean(name) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,56 @@
+package cfgo
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// envValueEscaper escapes the characters that godotenv unescapes inside double quotes.
+var envValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, `$`, `\$`)
+
+// WriteEnvFile writes every key visible through the config to path as sorted KEY="value" lines, quoted and
+// escaped so that loading the file back yields the same values. Keys are written with the config's prefix
+// so the file can be loaded by the same config. godotenv cannot read back a value that ends in a backslash,
+// or one that ends in a double quote and contains a single quote, so such a value is an error. Values are
+// not redacted, so a new file is only readable by its owner.
+func (e *EnvLoader) WriteEnvFile(path string) error {
+	env := e.environ()
+
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var b strings.Builder
+
+	for _, key := range keys {
+		value, ok := quoteEnvValue(env[key])
+		if !ok {
+			return fmt.Errorf("cannot write config %v: its value would not read back unchanged", e.envName(key))
+		}
+
+		b.WriteString(e.envName(key) + "=" + value + "\n")
+	}
+
+	return os.WriteFile(path, []byte(b.String()), 0o600)
+}
+
+// quoteEnvValue quotes v for an env file. Double quotes are used where possible; godotenv strips a trailing
+// quote character from the value, so a value ending in a double quote is single-quoted instead, which
+// works as long as it contains no single quote.
+func quoteEnvValue(v string) (string, bool) {
+	switch {
+	case strings.HasSuffix(v, `\`):
+		return "", false
+	case !strings.HasSuffix(v, `"`):
+		return `"` + envValueEscaper.Replace(v) + `"`, true
+	case !strings.Contains(v, `'`):
+		return `'` + v + `'`, true
+	default:
+		return "", false
+	}
+}
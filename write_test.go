@@ -0,0 +1,66 @@
+package cfgo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/joho/godotenv"
+)
+
+func TestWriteEnvFileRoundTrip(t *testing.T) {
+	values := map[string]string{
+		"CFGO_RT_ZEROS":     "007",
+		"CFGO_RT_SIGN":      "+5",
+		"CFGO_RT_QUOTES":    `say "hi"`,
+		"CFGO_RT_QUOTE_END": `it's "x`,
+		"CFGO_RT_DOLLAR":    "$HOME and ${PATH} and $",
+		"CFGO_RT_BACKSLASH": `C:\dir\file and \$HOME and \n`,
+		"CFGO_RT_NEWLINES":  "line1\nline2\r\nline3",
+		"CFGO_RT_HASH":      "a # not a comment",
+		"CFGO_RT_EMPTY":     "",
+		"CFGO_RT_SINGLE":    "'single'",
+	}
+
+	for k, v := range values {
+		t.Setenv(k, v)
+	}
+
+	conf := &EnvLoader{logger: testLogger{}, prefix: "CFGO_RT_", envState: &envState{}}
+	path := filepath.Join(t.TempDir(), ".env")
+
+	if err := conf.WriteEnvFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if perm := info.Mode().Perm(); perm&0o077 != 0 {
+		t.Errorf("file mode = %v, want no access for group and others", perm)
+	}
+
+	got, err := godotenv.Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for k, want := range values {
+		if got[k] != want {
+			t.Errorf("%s = %q, want %q", k, got[k], want)
+		}
+	}
+}
+
+func TestWriteEnvFileRejectsUnreadableValues(t *testing.T) {
+	for _, value := range []string{`ends with \`, `it's "quoted"`} {
+		t.Setenv("CFGO_RT_BAD", value)
+
+		conf := &EnvLoader{logger: testLogger{}, prefix: "CFGO_RT_", envState: &envState{}}
+		if err := conf.WriteEnvFile(filepath.Join(t.TempDir(), ".env")); err == nil {
+			t.Errorf("WriteEnvFile() with %q: expected an error", value)
+		}
+	}
+}
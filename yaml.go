@@ -0,0 +1,30 @@
+package cfgo
+
+import (
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadYAML reads the given YAML files and merges their values into the config using dotted keys, like
+// FromReader. Sequences are stored as JSON arrays and scalars keep their YAML representation, so true/false
+// and numbers read back through the typed getters.
+func (e *EnvLoader) LoadYAML(filenames ...string) error {
+	return e.loadFiles(parseYAML, filenames)
+}
+
+func LoadYAML(filenames ...string) error {
+	return configInstance.LoadYAML(filenames...)
+}
+
+func parseYAML(r io.Reader) (map[string]string, error) {
+	var doc map[string]any
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	flatten("", doc, values)
+
+	return values, nil
+}